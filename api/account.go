@@ -1,95 +0,0 @@
-package api
-
-import (
-	"database/sql"
-	"net/http"
-
-	db "github.com/chandiniv1/transfers-system/db/sqlc"
-	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
-)
-
-type createAccountRequest struct {
-	Account_ID int64  `json:"account_id" binding:"required,min=1"`
-	Currency   string `json:"currency" binding:"required,currency"`
-	Balance    int64  `json:"balance" binding:"required,min=0"`
-}
-
-func (server *Server) createAccount(ctx *gin.Context) {
-	var req createAccountRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.CreateAccountParams{
-		AccountID: req.Account_ID,
-		Balance:   req.Balance,
-		Currency:  req.Currency,
-	}
-
-	account, err := server.store.CreateAccount(ctx, arg)
-	if err != nil {
-		if pqErr, ok := err.(*pq.Error); ok {
-			switch pqErr.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
-}
-
-type getAccountRequest struct {
-	ID int64 `uri:"id" binding:"required,min=1"`
-}
-
-func (server *Server) getAccount(ctx *gin.Context) {
-	var req getAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	account, err := server.store.GetAccount(ctx, req.ID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
-}
-
-type listAccountsParams struct {
-	PageID   int64 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=1,max=10"`
-}
-
-func (server *Server) listAccounts(ctx *gin.Context) {
-	var req listAccountsParams
-	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	arg := db.ListAccountsParams{
-		Limit:  req.PageSize,
-		Offset: (int32(req.PageID - 1)) * req.PageSize,
-	}
-
-	account, err := server.store.ListAccounts(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, account)
-}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// errorStatus returns the HTTP status code an error returned by the store is reported with
+func errorStatus(err error) int {
+	if status, ok := util.ProblemStatus(db.ErrorProblemCode(err)); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// abortWithError stops the request and responds with the problem details of err.
+// Server errors are recorded on the context so the logger prints them instead of the client.
+func abortWithError(ctx *gin.Context, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		ctx.Error(err)
+	}
+
+	ctx.Header("Content-Type", util.ProblemContentType)
+	ctx.AbortWithStatusJSON(status, newProblem(status, err, ctx.Request.URL.Path))
+}
+
+// newProblem converts err into problem details that are safe to show to clients.
+// The detail is fixed by the code of the problem, so it never includes the error itself.
+func newProblem(status int, err error, instance string) util.Problem {
+	if status >= http.StatusInternalServerError {
+		return util.NewProblem(status, util.ProblemInternal, util.ProblemDetail(util.ProblemInternal), instance)
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		problem := util.NewProblem(status, util.ProblemValidationFailed, util.ProblemDetail(util.ProblemValidationFailed), instance)
+		problem.Errors = fieldErrors(validationErrs)
+		return problem
+	}
+
+	code := db.ErrorProblemCode(err)
+	if codeStatus, ok := util.ProblemStatus(code); !ok || codeStatus != status {
+		code = util.ProblemCode(status)
+	}
+	return util.NewProblem(status, code, util.ProblemDetail(code), instance)
+}
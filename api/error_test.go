@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// storeErr builds an error the way the store returns it: the sentinel wrapping the pgx error.
+func storeErr(sentinel error, err error) error {
+	return fmt.Errorf("failed to update balances: %w", fmt.Errorf("%w: %w", sentinel, err))
+}
+
+func TestErrorStatus(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{
+			name:   "Record Not Found",
+			err:    storeErr(db.ErrRecordNotFound, pgx.ErrNoRows),
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "Unique Violation",
+			err:    storeErr(db.ErrUniqueViolation, &pgconn.PgError{Code: db.UniqueViolation}),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "Foreign Key Violation",
+			err:    storeErr(db.ErrForeignKeyViolation, &pgconn.PgError{Code: db.ForeignKeyViolation}),
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "Insufficient Funds",
+			err:    storeErr(db.ErrInsufficientFunds, &pgconn.PgError{Code: db.CheckViolation, ConstraintName: "accounts_balance_check"}),
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "Check Violation",
+			err:    storeErr(db.ErrCheckViolation, &pgconn.PgError{Code: db.CheckViolation}),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "Amount Out Of Range",
+			err:    storeErr(db.ErrAmountOutOfRange, &pgconn.PgError{Code: db.NumericOutOfRange}),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "Serialization Failure",
+			err:    storeErr(db.ErrSerializationFailure, &pgconn.PgError{Code: db.SerializationFailure}),
+			status: http.StatusConflict,
+		},
+		{
+			name:   "Untranslated Postgres Error",
+			err:    &pgconn.PgError{Code: "57014"},
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "Other Error",
+			err:    errors.New("connection refused"),
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.status, errorStatus(tc.err))
+		})
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: db.UniqueViolation, Message: "duplicate key value violates unique constraint \"users_pkey\""}
+
+	problem := newProblem(http.StatusForbidden, storeErr(db.ErrUniqueViolation, pgErr), "/users")
+	require.Equal(t, "/problems/already_exists", problem.Type)
+	require.Equal(t, "Forbidden", problem.Title)
+	require.Equal(t, http.StatusForbidden, problem.Status)
+	require.Equal(t, util.ProblemAlreadyExists, problem.Code)
+	require.Equal(t, "/users", problem.Instance)
+	require.NotContains(t, problem.Detail, "users_pkey")
+
+	problem = newProblem(http.StatusInternalServerError, pgErr, "/users")
+	require.Equal(t, util.ProblemInternal, problem.Code)
+	require.NotContains(t, problem.Detail, "users_pkey")
+
+	problem = newProblem(http.StatusUnauthorized, errors.New("crypto/bcrypt: hashedPassword is not the hash of the given password"), "/users/login")
+	require.Equal(t, util.ProblemUnauthenticated, problem.Code)
+	require.Equal(t, util.ProblemDetail(util.ProblemUnauthenticated), problem.Detail)
+	require.NotContains(t, problem.Detail, "bcrypt")
+}
+
+func TestProblemResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	rec := httptest.NewRecorder()
+
+	body := []byte(`{"username": "not valid", "password": "123", "email": "not-an-email"}`)
+	req, err := http.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, util.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	var problem util.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	require.Equal(t, util.ProblemValidationFailed, problem.Code)
+	require.Equal(t, http.StatusBadRequest, problem.Status)
+	require.Equal(t, "/users", problem.Instance)
+	require.ElementsMatch(t, []util.FieldError{
+		{Field: "username", Code: "alphanum", Message: "must contain only letters and digits"},
+		{Field: "password", Code: "min", Message: "must be at least 6 characters long"},
+		{Field: "full_name", Code: "required", Message: "is required"},
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+	}, problem.Errors)
+}
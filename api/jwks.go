@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+)
+
+// getJWKS serves the public keys downstream services need to verify access tokens offline
+func (server *Server) getJWKS(ctx *gin.Context) {
+	provider := server.tokenMaker.(token.KeySetProvider)
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, provider.JWKS())
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateKey(t *testing.T) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "token_key.pem")
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestGetJWKSAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		config        func(t *testing.T) util.Config
+		checkResponse func(rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			config: func(t *testing.T) util.Config {
+				return util.Config{
+					TokenType:           token.TypeAsymmetricJWT,
+					TokenPrivateKeyPath: writePrivateKey(t),
+					AccessTokenDuration: time.Minute,
+				}
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var set token.JSONWebKeySet
+				err := json.Unmarshal(rec.Body.Bytes(), &set)
+				require.NoError(t, err)
+				require.Len(t, set.Keys, 1)
+				require.NotEmpty(t, set.Keys[0].KeyID)
+			},
+		},
+		{
+			name: "Symmetric Maker",
+			config: func(t *testing.T) util.Config {
+				return util.Config{
+					TokenSymmetricKey:   util.RandomString(32),
+					AccessTokenDuration: time.Minute,
+				}
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := NewServer(tc.config(t), nil)
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			req, err := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(rec, req)
+			tc.checkResponse(rec)
+		})
+	}
+}
+
+func TestNewServerMissingPrivateKey(t *testing.T) {
+	config := util.Config{
+		TokenType:           token.TypeAsymmetricJWT,
+		TokenPrivateKeyPath: filepath.Join(t.TempDir(), "missing.pem"),
+	}
+
+	server, err := NewServer(config, nil)
+	require.Error(t, err)
+	require.Nil(t, server)
+}
@@ -3,14 +3,23 @@ package api
 import (
 	"os"
 	"testing"
+	"time"
 
 	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/util"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
-	server, err := NewServer(store)
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+		IdempotencyKeyTTL:    time.Hour,
+	}
+
+	server, err := NewServer(config, store)
 	require.NoError(t, err)
 
 	return server
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	authorizationHeaderKey  = "authorization"
+	authorizationTypeBearer = "bearer"
+	authorizationPayloadKey = "authorization_payload"
+)
+
+// authMiddleware verifies the bearer token of the request, checks that its session
+// has not been revoked and stores its payload in the context.
+func authMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
+		if len(authorizationHeader) == 0 {
+			err := errors.New("authorization header is not provided")
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		fields := strings.Fields(authorizationHeader)
+		if len(fields) < 2 {
+			err := errors.New("invalid authorization header format")
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		authorizationType := strings.ToLower(fields[0])
+		if authorizationType != authorizationTypeBearer {
+			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		accessToken := fields[1]
+		payload, err := tokenMaker.VerifyToken(accessToken)
+		if err != nil {
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		if err := payload.CheckType(token.TokenTypeAccess); err != nil {
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		session, err := store.GetSession(ctx, payload.SessionID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				abortWithError(ctx, http.StatusUnauthorized, err)
+				return
+			}
+			abortWithError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		if session.IsBlocked {
+			err := errors.New("session is blocked")
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		if session.Username != payload.Username {
+			err := errors.New("incorrect session user")
+			abortWithError(ctx, http.StatusUnauthorized, err)
+			return
+		}
+
+		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Next()
+	}
+}
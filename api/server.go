@@ -1,24 +1,38 @@
 package api
 
 import (
+	"fmt"
+	"net/http"
+
 	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
 type Server struct {
-	store  db.Store
-	router *gin.Engine
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	router     *gin.Engine
 }
 
-func NewServer(store db.Store) (*Server, error) {
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	tokenMaker, err := token.NewMakerFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
 	server := &Server{
-		store: store,
+		config:     config,
+		store:      store,
+		tokenMaker: tokenMaker,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		v.RegisterValidation("currency", validCurrency)
+		v.RegisterTagNameFunc(fieldName)
 	}
 
 	server.setupRouter()
@@ -29,10 +43,16 @@ func NewServer(store db.Store) (*Server, error) {
 func (server *Server) setupRouter() {
 	router := gin.Default()
 
-	router.POST("/accounts", server.createAccount)
-	router.GET("/accounts/:id", server.getAccount)
-	router.GET("/accounts", server.listAccounts)
-	router.POST("/transactions", server.createTransfer)
+	router.POST("/users", server.createUser)
+	router.POST("/users/login", server.loginUser)
+	router.POST("/tokens/renew_access", server.renewAccessToken)
+
+	if _, ok := server.tokenMaker.(token.KeySetProvider); ok {
+		router.GET("/.well-known/jwks.json", server.getJWKS)
+	}
+
+	authRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker, server.store))
+	authRoutes.POST("/sessions/revoke", server.revokeSession)
 
 	server.router = router
 }
@@ -41,6 +61,12 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
-func errorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+// Handler returns the HTTP handler serving the API routes
+func (server *Server) Handler() http.Handler {
+	return server.router
+}
+
+// SetNoRouteHandler passes requests that match none of the API routes to handler
+func (server *Server) SetNoRouteHandler(handler http.Handler) {
+	server.router.NoRoute(gin.WrapH(handler))
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetNoRouteHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	server.SetNoRouteHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	for _, url := range []string{"/accounts", "/accounts/1", "/accounts/1/transactions", "/transactions/7", "/currencies"} {
+		rec := httptest.NewRecorder()
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusTeapot, rec.Code, url)
+	}
+
+	// Routes served by gin itself must not fall through.
+	rec := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/sessions/revoke", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
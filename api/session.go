@@ -0,0 +1,75 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type revokeSessionRequest struct {
+	// SessionID defaults to the session of the access token used for the request
+	SessionID string `json:"session_id" binding:"omitempty,uuid"`
+}
+
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Username  string    `json:"username"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+	IsBlocked bool      `json:"is_blocked"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		ClientIP:  session.ClientIp,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: session.ExpiresAt.Time,
+		CreatedAt: session.CreatedAt.Time,
+	}
+}
+
+func (server *Server) revokeSession(ctx *gin.Context) {
+	var req revokeSessionRequest
+	// an empty body revokes the current session
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	sessionID := authPayload.SessionID
+	if req.SessionID != "" {
+		sessionID = uuid.MustParse(req.SessionID)
+	}
+
+	session, err := server.store.GetSession(ctx, sessionID)
+	if err != nil {
+		abortWithError(ctx, errorStatus(err), err)
+		return
+	}
+
+	if session.Username != authPayload.Username {
+		err := errors.New("session doesn't belong to the authenticated user")
+		abortWithError(ctx, http.StatusForbidden, err)
+		return
+	}
+
+	session, err = server.store.BlockSession(ctx, session.ID)
+	if err != nil {
+		abortWithError(ctx, errorStatus(err), err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newSessionResponse(session))
+}
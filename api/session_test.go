@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeSessionAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	otherSessionID := uuid.New()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore, payload *token.Payload)
+		checkResponse func(rec *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Current Session",
+			body: nil,
+			buildStubs: func(store *mockdb.MockStore, payload *token.Payload) {
+				session := db.Session{ID: payload.SessionID, Username: user.Username}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+					Times(2).
+					Return(session, nil)
+
+				session.IsBlocked = true
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.NotContains(t, rec.Body.String(), "refresh_token")
+
+				var rsp sessionResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.True(t, rsp.IsBlocked)
+			},
+		},
+		{
+			name: "Other Session",
+			body: gin.H{"session_id": otherSessionID.String()},
+			buildStubs: func(store *mockdb.MockStore, payload *token.Payload) {
+				stubActiveSessionPayload(store, payload)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(otherSessionID)).
+					Times(1).
+					Return(db.Session{ID: otherSessionID, Username: user.Username}, nil)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Eq(otherSessionID)).
+					Times(1).
+					Return(db.Session{ID: otherSessionID, Username: user.Username, IsBlocked: true}, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+			},
+		},
+		{
+			name: "Session Of Another User",
+			body: gin.H{"session_id": otherSessionID.String()},
+			buildStubs: func(store *mockdb.MockStore, payload *token.Payload) {
+				stubActiveSessionPayload(store, payload)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(otherSessionID)).
+					Times(1).
+					Return(db.Session{ID: otherSessionID, Username: "other"}, nil)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, rec.Code)
+			},
+		},
+		{
+			name: "Invalid Session ID",
+			body: gin.H{"session_id": "invalid"},
+			buildStubs: func(store *mockdb.MockStore, payload *token.Payload) {
+				stubActiveSessionPayload(store, payload)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+			},
+		},
+		{
+			name: "Block Session Error",
+			body: gin.H{"session_id": otherSessionID.String()},
+			buildStubs: func(store *mockdb.MockStore, payload *token.Payload) {
+				stubActiveSessionPayload(store, payload)
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(otherSessionID)).
+					Times(1).
+					Return(db.Session{ID: otherSessionID, Username: user.Username}, nil)
+				store.EXPECT().
+					BlockSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, errors.New("db error"))
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, rec.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			accessToken, payload, err := server.tokenMaker.CreateToken(user.Username, uuid.New(), token.TokenTypeAccess, time.Minute)
+			require.NoError(t, err)
+			tc.buildStubs(store, payload)
+
+			rec := httptest.NewRecorder()
+
+			var body *bytes.Reader
+			if tc.body != nil {
+				data, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+				body = bytes.NewReader(data)
+			} else {
+				body = bytes.NewReader(nil)
+			}
+
+			url := "/sessions/revoke"
+			req, err := http.NewRequest(http.MethodPost, url, body)
+			require.NoError(t, err)
+			req.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+
+			server.router.ServeHTTP(rec, req)
+			tc.checkResponse(rec)
+		})
+	}
+}
+
+// stubActiveSessionPayload makes the auth middleware accept the session of the payload once.
+func stubActiveSessionPayload(store *mockdb.MockStore, payload *token.Payload) {
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+		Times(1).
+		Return(db.Session{ID: payload.SessionID, Username: payload.Username}, nil)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+)
+
+type renewAccessTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type renewAccessTokenResponse struct {
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+func (server *Server) renewAccessToken(ctx *gin.Context) {
+	var req renewAccessTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		abortWithError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+	if err != nil {
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := refreshPayload.CheckType(token.TokenTypeRefresh); err != nil {
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	session, err := server.store.GetSession(ctx, refreshPayload.SessionID)
+	if errors.Is(err, db.ErrRecordNotFound) {
+		abortWithError(ctx, http.StatusUnauthorized, errors.New("session not found"))
+		return
+	}
+	if err != nil {
+		abortWithError(ctx, errorStatus(err), err)
+		return
+	}
+
+	if session.IsBlocked {
+		err := errors.New("session is blocked")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if session.Username != refreshPayload.Username {
+		err := errors.New("incorrect session user")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if session.RefreshToken != req.RefreshToken {
+		err := errors.New("mismatched session token")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	if time.Now().After(session.ExpiresAt.Time) {
+		err := errors.New("expired session")
+		abortWithError(ctx, http.StatusUnauthorized, err)
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
+		refreshPayload.Username,
+		session.ID,
+		token.TokenTypeAccess,
+		server.config.AccessTokenDuration,
+	)
+	if err != nil {
+		abortWithError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	rsp := renewAccessTokenResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpiredAt,
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore, refreshToken string, payload *token.Payload)
+		checkResponse func(rec *httptest.ResponseRecorder, payload *token.Payload)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := db.Session{
+					ID:           payload.SessionID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					ExpiresAt:    pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+				}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var rsp renewAccessTokenResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotEmpty(t, rsp.AccessToken)
+			},
+		},
+		{
+			name: "Session Not Found",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name: "Blocked Session",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := db.Session{
+					ID:           payload.SessionID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					IsBlocked:    true,
+					ExpiresAt:    pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+				}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name: "Mismatched Refresh Token",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := db.Session{
+					ID:           payload.SessionID,
+					Username:     user.Username,
+					RefreshToken: "other",
+					ExpiresAt:    pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+				}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name: "Expired Session",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				session := db.Session{
+					ID:           payload.SessionID,
+					Username:     user.Username,
+					RefreshToken: refreshToken,
+					ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+				}
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(session, nil)
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name: "Internal Error",
+			buildStubs: func(store *mockdb.MockStore, refreshToken string, payload *token.Payload) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Session{}, errors.New("db error"))
+			},
+			checkResponse: func(rec *httptest.ResponseRecorder, payload *token.Payload) {
+				require.Equal(t, http.StatusInternalServerError, rec.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			refreshToken, payload, err := server.tokenMaker.CreateToken(user.Username, uuid.Nil, token.TokenTypeRefresh, time.Hour)
+			require.NoError(t, err)
+			tc.buildStubs(store, refreshToken, payload)
+
+			rec := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"refresh_token": refreshToken})
+			require.NoError(t, err)
+
+			url := "/tokens/renew_access"
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(rec, req)
+			tc.checkResponse(rec, payload)
+		})
+	}
+}
+
+func TestRenewAccessTokenInvalidToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	rec := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"refresh_token": "invalid"})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRenewAccessTokenWithAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	rec := httptest.NewRecorder()
+
+	user, _ := randomUser(t)
+	accessToken, _, err := server.tokenMaker.CreateToken(user.Username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(gin.H{"refresh_token": accessToken})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "/tokens/renew_access", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -1,71 +0,0 @@
-package api
-
-import (
-	"database/sql"
-	"errors"
-	"net/http"
-
-	db "github.com/chandiniv1/transfers-system/db/sqlc"
-	"github.com/gin-gonic/gin"
-)
-
-type createTransferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
-}
-
-func (server *Server) createTransfer(ctx *gin.Context) {
-	var req createTransferRequest
-	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return
-	}
-
-	_, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
-	if !valid {
-		return
-	}
-
-	// TODO: add authentication
-
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
-	if !valid {
-		return
-	}
-
-	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
-	}
-
-	result, err := server.store.TransferTx(ctx, arg)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	ctx.JSON(http.StatusOK, result)
-}
-
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
-	account, err := server.store.GetAccount(ctx, accountID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return account, false
-		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return account, false
-	}
-
-	if account.Currency != currency {
-		err := errors.New("account currency mismatch")
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
-		return account, false
-	}
-
-	return account, true
-}
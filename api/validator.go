@@ -1,13 +1,65 @@
 package api
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/chandiniv1/transfers-system/util"
 	"github.com/go-playground/validator/v10"
 )
 
-var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
-	if currency, ok := fieldLevel.Field().Interface().(string); ok {
-		return util.IsSupportedCurrency(currency)
+// fieldName reports a struct field by the name clients send it as, so validation errors
+// refer to "from_account_id" rather than "FromAccountID".
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldErrors translates validation errors into messages the frontend can show next to each field
+func fieldErrors(errs validator.ValidationErrors) []util.FieldError {
+	fields := make([]util.FieldError, 0, len(errs))
+	for _, fieldErr := range errs {
+		fields = append(fields, util.FieldError{
+			Field:   fieldErr.Field(),
+			Code:    fieldErr.Tag(),
+			Message: fieldMessage(fieldErr),
+		})
+	}
+	return fields
+}
+
+func fieldMessage(fieldErr validator.FieldError) string {
+	isString := fieldErr.Kind() == reflect.String
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if isString {
+			return fmt.Sprintf("must be at least %s characters long", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fieldErr.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("must be at most %s characters long", fieldErr.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fieldErr.Param())
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "email":
+		return "must be a valid email address"
+	case "uuid":
+		return "must be a valid UUID"
+	default:
+		return "is invalid"
 	}
-	return false
 }
@@ -0,0 +1,9 @@
+-- Create users table
+CREATE TABLE users (
+  username varchar PRIMARY KEY,
+  hashed_password varchar NOT NULL,
+  full_name varchar NOT NULL,
+  email varchar UNIQUE NOT NULL,
+  password_changed_at timestamptz NOT NULL DEFAULT '0001-01-01 00:00:00Z',
+  created_at timestamptz NOT NULL DEFAULT now()
+);
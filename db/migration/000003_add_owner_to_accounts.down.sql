@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_account_owner;
+ALTER TABLE IF EXISTS accounts DROP CONSTRAINT IF EXISTS fk_account_owner;
+ALTER TABLE IF EXISTS accounts DROP COLUMN IF EXISTS owner;
+
+-- The user the up migration gave the accounts without an owner to
+DELETE FROM users WHERE username = 'unclaimed';
@@ -0,0 +1,18 @@
+-- Link accounts to the users that own them
+ALTER TABLE accounts ADD COLUMN owner varchar;
+
+-- Accounts opened before ownership existed go to a user that can't log in,
+-- so they stay reachable by an operator without being claimed by anyone else
+INSERT INTO users (username, hashed_password, full_name, email)
+SELECT 'unclaimed', '', 'Unclaimed accounts', 'unclaimed@invalid'
+WHERE EXISTS (SELECT 1 FROM accounts WHERE owner IS NULL)
+ON CONFLICT (username) DO NOTHING;
+
+UPDATE accounts SET owner = 'unclaimed' WHERE owner IS NULL;
+
+ALTER TABLE accounts ALTER COLUMN owner SET NOT NULL;
+ALTER TABLE accounts
+  ADD CONSTRAINT fk_account_owner
+    FOREIGN KEY (owner)
+    REFERENCES users(username);
+CREATE INDEX idx_account_owner ON accounts(owner);
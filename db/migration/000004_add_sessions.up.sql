@@ -0,0 +1,18 @@
+-- Create sessions table, keyed by the ID of the refresh token payload
+CREATE TABLE sessions (
+  id uuid PRIMARY KEY,
+  username varchar NOT NULL,
+  refresh_token varchar NOT NULL,
+  user_agent varchar NOT NULL,
+  client_ip varchar NOT NULL,
+  is_blocked boolean NOT NULL DEFAULT false,
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_session_user
+    FOREIGN KEY (username)
+    REFERENCES users(username)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_session_username ON sessions(username);
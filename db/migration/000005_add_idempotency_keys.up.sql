@@ -0,0 +1,18 @@
+-- Create idempotency_keys table storing the response of each keyed transfer
+CREATE TABLE idempotency_keys (
+  username varchar NOT NULL,
+  key varchar NOT NULL,
+  request_hash varchar NOT NULL,
+  response_body bytea NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  expires_at timestamptz NOT NULL,
+
+  PRIMARY KEY (username, key),
+
+  CONSTRAINT fk_idempotency_key_user
+    FOREIGN KEY (username)
+    REFERENCES users(username)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_keys(expires_at);
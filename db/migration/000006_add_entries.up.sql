@@ -0,0 +1,31 @@
+-- Create entries table: one row per movement of money on an account
+CREATE TABLE entries (
+  id bigserial PRIMARY KEY,
+  account_id bigint NOT NULL,
+  -- NULL for the opening entry recorded when an account is created
+  transaction_id bigint,
+  -- signed amount: negative for debits, positive for credits
+  amount bigint NOT NULL CHECK (amount <> 0),
+  -- balance of the account right after this entry
+  balance bigint NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_entry_account
+    FOREIGN KEY (account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_entry_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_entry_account ON entries(account_id, id);
+CREATE INDEX idx_entry_transaction ON entries(transaction_id);
+
+-- Existing accounts get a single opening entry so their balances can be proven from entries
+INSERT INTO entries (account_id, amount, balance)
+SELECT account_id, balance, balance
+FROM accounts
+WHERE balance <> 0;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_account_owner_account_id;
+
+CREATE INDEX idx_account_owner ON accounts(owner);
@@ -0,0 +1,4 @@
+-- Serve keyset pages of an owner's accounts straight from the index
+DROP INDEX IF EXISTS idx_account_owner;
+
+CREATE INDEX idx_account_owner_account_id ON accounts(owner, account_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS holds;
+
+ALTER TABLE IF EXISTS accounts DROP CONSTRAINT IF EXISTS accounts_available_balance_check;
+ALTER TABLE IF EXISTS accounts DROP COLUMN IF EXISTS held_balance;
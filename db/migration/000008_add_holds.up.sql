@@ -0,0 +1,41 @@
+-- Funds reserved by active holds; they can't be transferred until the hold is captured, voided or expires
+ALTER TABLE accounts
+  ADD COLUMN held_balance bigint NOT NULL DEFAULT 0 CHECK (held_balance >= 0),
+  ADD CONSTRAINT accounts_available_balance_check CHECK (balance >= held_balance);
+
+-- Create holds table: one row per authorization placed on an account
+CREATE TABLE holds (
+  id bigserial PRIMARY KEY,
+  account_id bigint NOT NULL,
+  to_account_id bigint NOT NULL,
+  amount bigint NOT NULL CHECK (amount > 0),
+  status varchar NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'captured', 'voided', 'expired')),
+  -- set when the hold is captured, possibly for less than its amount
+  captured_amount bigint,
+  transaction_id bigint,
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  resolved_at timestamptz,
+
+  CONSTRAINT fk_hold_account
+    FOREIGN KEY (account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_hold_to_account
+    FOREIGN KEY (to_account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_hold_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE SET NULL,
+
+  CONSTRAINT check_hold_account_not_eq_to_account
+    CHECK (account_id <> to_account_id)
+);
+
+CREATE INDEX idx_hold_account ON holds(account_id);
+CREATE INDEX idx_hold_active_expires_at ON holds(expires_at) WHERE status = 'active';
@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS transactions
+  DROP CONSTRAINT IF EXISTS check_reversal_not_reversed,
+  DROP CONSTRAINT IF EXISTS transactions_reversed_amount_check,
+  DROP CONSTRAINT IF EXISTS fk_transaction_original,
+  DROP COLUMN IF EXISTS reversed_amount,
+  DROP COLUMN IF EXISTS reason,
+  DROP COLUMN IF EXISTS original_transaction_id;
@@ -0,0 +1,16 @@
+-- A reversal is a compensating transaction linked to the transaction it refunds, fully or in part
+ALTER TABLE transactions
+  ADD COLUMN original_transaction_id bigint,
+  ADD COLUMN reason varchar,
+  -- total amount refunded by the reversals of this transaction
+  ADD COLUMN reversed_amount bigint NOT NULL DEFAULT 0,
+  ADD CONSTRAINT fk_transaction_original
+    FOREIGN KEY (original_transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE CASCADE,
+  ADD CONSTRAINT transactions_reversed_amount_check
+    CHECK (reversed_amount >= 0 AND reversed_amount <= amount),
+  ADD CONSTRAINT check_reversal_not_reversed
+    CHECK (original_transaction_id IS NULL OR reversed_amount = 0);
+
+CREATE INDEX idx_transaction_original ON transactions(original_transaction_id);
@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS transactions
+  DROP CONSTRAINT IF EXISTS check_conversion_complete,
+  DROP COLUMN IF EXISTS exchange_rate,
+  DROP COLUMN IF EXISTS destination_amount;
@@ -0,0 +1,7 @@
+-- A converted transaction debits amount in the source currency and credits destination_amount
+-- in the destination currency, at exchange_rate destination units per source unit
+ALTER TABLE transactions
+  ADD COLUMN destination_amount bigint CHECK (destination_amount > 0),
+  ADD COLUMN exchange_rate numeric(18, 8) CHECK (exchange_rate > 0),
+  ADD CONSTRAINT check_conversion_complete
+    CHECK ((destination_amount IS NULL) = (exchange_rate IS NULL));
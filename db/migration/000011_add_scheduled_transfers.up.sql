@@ -0,0 +1,37 @@
+-- Create scheduled_transfers table: transfers to run once execute_at has passed
+CREATE TABLE scheduled_transfers (
+  id bigserial PRIMARY KEY,
+  from_account_id bigint NOT NULL,
+  to_account_id bigint NOT NULL,
+  amount bigint NOT NULL CHECK (amount > 0),
+  execute_at timestamptz NOT NULL,
+  status varchar NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'executed', 'failed', 'canceled')),
+  -- set when the transfer is executed
+  transaction_id bigint,
+  -- set when the transfer fails, such as for insufficient funds
+  failure_reason varchar,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  resolved_at timestamptz,
+
+  CONSTRAINT fk_scheduled_transfer_from_account
+    FOREIGN KEY (from_account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_scheduled_transfer_to_account
+    FOREIGN KEY (to_account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_scheduled_transfer_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE SET NULL,
+
+  CONSTRAINT check_scheduled_transfer_from_not_eq_to
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX idx_scheduled_transfer_from_account ON scheduled_transfers(from_account_id);
+CREATE INDEX idx_scheduled_transfer_pending_execute_at ON scheduled_transfers(execute_at) WHERE status = 'pending';
@@ -0,0 +1,67 @@
+-- Create standing_orders table: transfers repeating on a schedule
+CREATE TABLE standing_orders (
+  id bigserial PRIMARY KEY,
+  from_account_id bigint NOT NULL,
+  to_account_id bigint NOT NULL,
+  amount bigint NOT NULL CHECK (amount > 0),
+  -- daily, weekly, monthly or a cron expression
+  schedule varchar NOT NULL,
+  start_at timestamptz NOT NULL,
+  -- no occurrence runs after end_at
+  end_at timestamptz,
+  max_occurrences integer CHECK (max_occurrences > 0),
+  insufficient_funds_policy varchar NOT NULL DEFAULT 'skip'
+    CHECK (insufficient_funds_policy IN ('skip', 'retry')),
+  status varchar NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'completed', 'canceled')),
+  -- the occurrence to run next
+  next_run_at timestamptz NOT NULL,
+  -- set while the occurrence at next_run_at waits to be retried
+  retry_at timestamptz,
+  retries integer NOT NULL DEFAULT 0,
+  -- occurrences executed or skipped so far
+  occurrences integer NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  ended_at timestamptz,
+
+  CONSTRAINT fk_standing_order_from_account
+    FOREIGN KEY (from_account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_standing_order_to_account
+    FOREIGN KEY (to_account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT check_standing_order_from_not_eq_to
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX idx_standing_order_from_account ON standing_orders(from_account_id);
+CREATE INDEX idx_standing_order_active_run_at ON standing_orders((COALESCE(retry_at, next_run_at))) WHERE status = 'active';
+
+-- Create standing_order_executions table: one row per attempt to run an occurrence of a standing order
+CREATE TABLE standing_order_executions (
+  id bigserial PRIMARY KEY,
+  standing_order_id bigint NOT NULL,
+  occurrence_at timestamptz NOT NULL,
+  -- failed attempts are retried; skipped occurrences are not
+  status varchar NOT NULL CHECK (status IN ('executed', 'failed', 'skipped')),
+  -- set when the occurrence is executed
+  transaction_id bigint,
+  failure_reason varchar,
+  created_at timestamptz NOT NULL DEFAULT now(),
+
+  CONSTRAINT fk_standing_order_execution_order
+    FOREIGN KEY (standing_order_id)
+    REFERENCES standing_orders(id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_standing_order_execution_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE SET NULL
+);
+
+CREATE INDEX idx_standing_order_execution_order ON standing_order_executions(standing_order_id, id);
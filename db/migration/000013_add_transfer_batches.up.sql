@@ -0,0 +1,43 @@
+-- Create transfer_batches table: a list of transfers submitted in one request
+CREATE TABLE transfer_batches (
+  id bigserial PRIMARY KEY,
+  owner varchar NOT NULL,
+  -- atomic batches run every transfer or none; best_effort batches run each on its own
+  mode varchar NOT NULL CHECK (mode IN ('atomic', 'best_effort')),
+  status varchar NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'completed', 'partial', 'failed')),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  completed_at timestamptz,
+
+  CONSTRAINT fk_transfer_batch_owner
+    FOREIGN KEY (owner)
+    REFERENCES users(username)
+    ON DELETE CASCADE
+);
+
+-- Create transfer_batch_items table: the outcome of every transfer of a batch
+CREATE TABLE transfer_batch_items (
+  batch_id bigint NOT NULL,
+  -- index of the transfer in the request
+  position integer NOT NULL,
+  from_account_id bigint NOT NULL,
+  to_account_id bigint NOT NULL,
+  amount bigint NOT NULL,
+  status varchar NOT NULL CHECK (status IN ('succeeded', 'failed')),
+  -- set when the transfer succeeded
+  transaction_id bigint,
+  -- set when the transfer failed
+  error varchar,
+
+  PRIMARY KEY (batch_id, position),
+
+  CONSTRAINT fk_transfer_batch_item_batch
+    FOREIGN KEY (batch_id)
+    REFERENCES transfer_batches(id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_transfer_batch_item_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE SET NULL
+);
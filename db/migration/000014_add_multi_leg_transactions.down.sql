@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS transaction_legs;
+
+-- fails while multi-leg transactions remain, as they have no destination to restore
+ALTER TABLE IF EXISTS transactions
+  DROP CONSTRAINT IF EXISTS check_multi_leg_not_reversal,
+  ALTER COLUMN destination_account_id SET NOT NULL;
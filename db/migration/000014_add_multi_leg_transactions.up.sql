@@ -0,0 +1,30 @@
+-- A multi-leg transaction debits its source account and credits several accounts; it has no single
+-- destination and records each movement as a leg instead
+ALTER TABLE transactions
+  ALTER COLUMN destination_account_id DROP NOT NULL,
+  ADD CONSTRAINT check_multi_leg_not_reversal
+    CHECK (destination_account_id IS NOT NULL OR original_transaction_id IS NULL);
+
+-- Create transaction_legs table: the signed movements of a multi-leg transaction, which sum to zero
+CREATE TABLE transaction_legs (
+  transaction_id bigint NOT NULL,
+  -- the debit of the source account comes first, then the credits in the order they were given
+  position integer NOT NULL,
+  account_id bigint NOT NULL,
+  amount bigint NOT NULL CHECK (amount <> 0),
+  memo varchar NOT NULL DEFAULT '',
+
+  PRIMARY KEY (transaction_id, position),
+
+  CONSTRAINT fk_transaction_leg_transaction
+    FOREIGN KEY (transaction_id)
+    REFERENCES transactions(id)
+    ON DELETE CASCADE,
+
+  CONSTRAINT fk_transaction_leg_account
+    FOREIGN KEY (account_id)
+    REFERENCES accounts(account_id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX idx_transaction_leg_account ON transaction_legs(account_id);
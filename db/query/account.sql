@@ -1,6 +1,6 @@
 -- name: CreateAccount :one
-INSERT INTO accounts (account_id, balance, currency)
-VALUES ($1, $2, $3)
+INSERT INTO accounts (account_id, owner, balance, currency)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetAccount :one
@@ -8,6 +8,25 @@ SELECT * FROM accounts
 WHERE account_id = $1
 LIMIT 1;
 
+-- name: GetAccountForUpdate :one
+-- Locks the row until the end of the transaction without blocking inserts that reference it.
+SELECT * FROM accounts
+WHERE account_id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: GetAccountsForUpdate :many
+-- Locks the rows in account_id order, so transactions locking overlapping sets of accounts can't deadlock.
+SELECT * FROM accounts
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[])
+ORDER BY account_id
+FOR NO KEY UPDATE;
+
+-- name: ListAccountsByID :many
+SELECT * FROM accounts
+WHERE account_id = ANY(sqlc.arg(account_ids)::bigint[])
+ORDER BY account_id;
+
 -- name: UpdateBalance :one
 UPDATE accounts
 SET balance = balance + sqlc.arg(amount)
@@ -15,9 +34,23 @@ WHERE account_id = sqlc.arg(account_id)
 RETURNING *;
 
 -- name: ListAccounts :many
+-- Keyset pagination over account_id; pass 0 as after_account_id for the first page.
 SELECT * FROM accounts
+WHERE owner = sqlc.arg(owner)
+  AND account_id > sqlc.arg(after_account_id)
+  AND (sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency))
+  AND (sqlc.narg(min_balance)::bigint IS NULL OR balance >= sqlc.narg(min_balance))
 ORDER BY account_id
-LIMIT $1
-OFFSET $2;
+LIMIT sqlc.arg(page_limit);
 
+-- name: AddHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount)
+WHERE account_id = sqlc.arg(account_id)
+RETURNING *;
 
+-- name: HasAccountInCurrency :one
+SELECT EXISTS (
+  SELECT 1 FROM accounts
+  WHERE owner = $1 AND currency = $2
+);
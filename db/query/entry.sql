@@ -0,0 +1,30 @@
+-- name: CreateEntry :one
+INSERT INTO entries (
+  account_id,
+  transaction_id,
+  amount,
+  balance
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetEntry :one
+SELECT * FROM entries
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListEntries :many
+-- Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+SELECT * FROM entries
+WHERE account_id = sqlc.arg(account_id)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListBalanceMismatches :many
+-- Accounts whose balance differs from the sum of their entries; empty when the ledger is consistent.
+SELECT a.account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.account_id
+GROUP BY a.account_id, a.balance
+HAVING a.balance <> COALESCE(SUM(e.amount), 0);
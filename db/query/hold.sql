@@ -0,0 +1,38 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+  account_id,
+  to_account_id,
+  amount,
+  expires_at
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ResolveHold :one
+-- Moves an active hold to its final status; captured_amount and transaction_id are only set on capture.
+UPDATE holds
+SET status = sqlc.arg(status),
+    captured_amount = sqlc.narg(captured_amount),
+    transaction_id = sqlc.narg(transaction_id),
+    resolved_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListExpiredHolds :many
+-- Active holds past their deadline, oldest first.
+SELECT id FROM holds
+WHERE status = 'active'
+  AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1;
@@ -0,0 +1,33 @@
+-- name: CreateIdempotencyKey :one
+-- Expired keys are taken over; a live key yields no row.
+INSERT INTO idempotency_keys (
+  username,
+  key,
+  request_hash,
+  response_body,
+  expires_at
+)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (username, key) DO UPDATE
+SET request_hash = EXCLUDED.request_hash,
+    response_body = EXCLUDED.response_body,
+    created_at = now(),
+    expires_at = EXCLUDED.expires_at
+WHERE idempotency_keys.expires_at <= now()
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE username = $1 AND key = $2 AND expires_at > now()
+LIMIT 1;
+
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+-- Deletes at most $1 expired keys, oldest first.
+DELETE FROM idempotency_keys
+WHERE (username, key) IN (
+  SELECT username, key FROM idempotency_keys
+  WHERE expires_at <= now()
+  ORDER BY expires_at
+  LIMIT $1
+);
@@ -0,0 +1,40 @@
+-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  execute_at
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetScheduledTransfer :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetScheduledTransferForUpdate :one
+SELECT * FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ClaimDueScheduledTransfer :one
+-- Locks the oldest pending transfer that is due, skipping those another worker is executing.
+SELECT * FROM scheduled_transfers
+WHERE status = 'pending'
+  AND execute_at <= now()
+ORDER BY execute_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: ResolveScheduledTransfer :one
+-- Moves a pending transfer to its final status; transaction_id is only set on execution
+-- and failure_reason on failure.
+UPDATE scheduled_transfers
+SET status = sqlc.arg(status),
+    transaction_id = sqlc.narg(transaction_id),
+    failure_reason = sqlc.narg(failure_reason),
+    resolved_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
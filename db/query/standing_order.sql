@@ -0,0 +1,82 @@
+-- name: CreateStandingOrder :one
+INSERT INTO standing_orders (
+  from_account_id,
+  to_account_id,
+  amount,
+  schedule,
+  start_at,
+  end_at,
+  max_occurrences,
+  insufficient_funds_policy,
+  next_run_at
+)
+VALUES (
+  sqlc.arg(from_account_id),
+  sqlc.arg(to_account_id),
+  sqlc.arg(amount),
+  sqlc.arg(schedule),
+  sqlc.arg(start_at),
+  sqlc.narg(end_at),
+  sqlc.narg(max_occurrences),
+  sqlc.arg(insufficient_funds_policy),
+  sqlc.arg(next_run_at)
+)
+RETURNING *;
+
+-- name: GetStandingOrder :one
+SELECT * FROM standing_orders
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetStandingOrderForUpdate :one
+SELECT * FROM standing_orders
+WHERE id = $1
+LIMIT 1
+FOR UPDATE;
+
+-- name: ClaimDueStandingOrder :one
+-- Locks the active order whose occurrence or retry is most overdue, skipping those another worker is running.
+SELECT * FROM standing_orders
+WHERE status = 'active'
+  AND COALESCE(retry_at, next_run_at) <= now()
+ORDER BY COALESCE(retry_at, next_run_at)
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateStandingOrderRun :one
+-- Records the progress of an order; ended_at is set once it is no longer active.
+UPDATE standing_orders
+SET status = sqlc.arg(status),
+    next_run_at = sqlc.arg(next_run_at),
+    retry_at = sqlc.narg(retry_at),
+    retries = sqlc.arg(retries),
+    occurrences = sqlc.arg(occurrences),
+    ended_at = CASE WHEN sqlc.arg(status)::varchar = 'active' THEN NULL ELSE now() END
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateStandingOrderExecution :one
+-- transaction_id is only set on execution and failure_reason otherwise.
+INSERT INTO standing_order_executions (
+  standing_order_id,
+  occurrence_at,
+  status,
+  transaction_id,
+  failure_reason
+)
+VALUES (
+  sqlc.arg(standing_order_id),
+  sqlc.arg(occurrence_at),
+  sqlc.arg(status),
+  sqlc.narg(transaction_id),
+  sqlc.narg(failure_reason)
+)
+RETURNING *;
+
+-- name: ListStandingOrderExecutions :many
+-- Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+SELECT * FROM standing_order_executions
+WHERE standing_order_id = sqlc.arg(standing_order_id)
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
@@ -1,9 +1,108 @@
 -- name: CreateTransaction :one
+-- original_transaction_id and reason are only set on reversals,
+-- destination_amount and exchange_rate only on conversions.
 INSERT INTO transactions (
   source_account_id,
   destination_account_id,
+  amount,
+  original_transaction_id,
+  reason,
+  destination_amount,
+  exchange_rate
+)
+VALUES (
+  sqlc.arg(source_account_id),
+  sqlc.arg(destination_account_id)::bigint,
+  sqlc.arg(amount),
+  sqlc.narg(original_transaction_id),
+  sqlc.narg(reason),
+  sqlc.narg(destination_amount),
+  sqlc.narg(exchange_rate)
+)
+RETURNING *;
+
+-- name: CreateMultiLegTransaction :one
+-- A multi-leg transaction has no destination; its credits are recorded as legs.
+INSERT INTO transactions (
+  source_account_id,
   amount
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: CreateTransactionLeg :one
+INSERT INTO transaction_legs (
+  transaction_id,
+  position,
+  account_id,
+  amount,
+  memo
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListTransactionLegs :many
+SELECT * FROM transaction_legs
+WHERE transaction_id = $1
+ORDER BY position;
+
+-- name: ListTransactionLegsByTransactions :many
+SELECT * FROM transaction_legs
+WHERE transaction_id = ANY(sqlc.arg(transaction_ids)::bigint[])
+ORDER BY transaction_id, position;
+
+-- name: GetTransaction :one
+SELECT * FROM transactions
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTransactionForUpdate :one
+SELECT * FROM transactions
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: AddReversedAmount :one
+UPDATE transactions
+SET reversed_amount = reversed_amount + sqlc.arg(amount)
+WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: ListAccountTransactions :many
+-- Keyset pagination over (created_at, id), newest first. Each direction is matched
+-- separately so the planner can combine idx_source_account and idx_destination_account;
+-- multi-leg transactions are incoming for the accounts they credit. account_amount is
+-- what the transaction moved for the account, its credit on a multi-leg transaction it
+-- didn't pay for, and is the amount min_amount and max_amount filter on.
+SELECT sqlc.embed(transactions), movement.account_amount
+FROM transactions
+CROSS JOIN LATERAL (
+  SELECT CASE
+    WHEN destination_account_id IS NULL AND source_account_id <> sqlc.arg(account_id) THEN (
+      SELECT COALESCE(SUM(l.amount), 0) FROM transaction_legs l
+      WHERE l.transaction_id = transactions.id AND l.account_id = sqlc.arg(account_id) AND l.amount > 0
+    )
+    ELSE amount
+  END::bigint AS account_amount
+) movement
+WHERE (
+    (source_account_id = sqlc.arg(account_id) AND sqlc.arg(include_outgoing)::boolean)
+    OR (destination_account_id = sqlc.arg(account_id) AND sqlc.arg(include_incoming)::boolean)
+    OR (
+      destination_account_id IS NULL AND sqlc.arg(include_incoming)::boolean
+      AND EXISTS (
+        SELECT 1 FROM transaction_legs l
+        WHERE l.transaction_id = transactions.id AND l.account_id = sqlc.arg(account_id) AND l.amount > 0
+      )
+    )
+  )
+  AND (sqlc.narg(min_amount)::bigint IS NULL OR movement.account_amount >= sqlc.narg(min_amount))
+  AND (sqlc.narg(max_amount)::bigint IS NULL OR movement.account_amount <= sqlc.narg(max_amount))
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from))
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to))
+  AND (
+    sqlc.narg(cursor_created_at)::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)::bigint)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,48 @@
+-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+  owner,
+  mode
+)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetTransferBatch :one
+SELECT * FROM transfer_batches
+WHERE id = $1
+LIMIT 1;
+
+-- name: CompleteTransferBatch :one
+UPDATE transfer_batches
+SET status = sqlc.arg(status),
+    completed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateTransferBatchItem :one
+-- transaction_id is only set when the transfer succeeded and error when it failed.
+INSERT INTO transfer_batch_items (
+  batch_id,
+  position,
+  from_account_id,
+  to_account_id,
+  amount,
+  status,
+  transaction_id,
+  error
+)
+VALUES (
+  sqlc.arg(batch_id),
+  sqlc.arg(position),
+  sqlc.arg(from_account_id),
+  sqlc.arg(to_account_id),
+  sqlc.arg(amount),
+  sqlc.arg(status),
+  sqlc.narg(transaction_id),
+  sqlc.narg(error)
+)
+RETURNING *;
+
+-- name: ListTransferBatchItems :many
+SELECT * FROM transfer_batch_items
+WHERE batch_id = $1
+ORDER BY position;
@@ -0,0 +1,21 @@
+-- name: CreateUser :one
+INSERT INTO users (
+  username,
+  hashed_password,
+  full_name,
+  email
+)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetUser :one
+SELECT * FROM users
+WHERE username = $1
+LIMIT 1;
+
+-- name: GetUserForUpdate :one
+-- Serializes changes to what the user owns without blocking inserts that reference the user.
+SELECT * FROM users
+WHERE username = $1
+LIMIT 1
+FOR NO KEY UPDATE;
@@ -7,34 +7,70 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addHeldBalance = `-- name: AddHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE account_id = $2
+RETURNING account_id, balance, currency, created_at, owner, held_balance
+`
+
+type AddHeldBalanceParams struct {
+	Amount    int64 `json:"amount"`
+	AccountID int64 `json:"account_id"`
+}
+
+func (q *Queries) AddHeldBalance(ctx context.Context, arg AddHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addHeldBalance, arg.Amount, arg.AccountID)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.HeldBalance,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts (account_id, balance, currency)
-VALUES ($1, $2, $3)
-RETURNING account_id, balance, currency, created_at
+INSERT INTO accounts (account_id, owner, balance, currency)
+VALUES ($1, $2, $3, $4)
+RETURNING account_id, balance, currency, created_at, owner, held_balance
 `
 
 type CreateAccountParams struct {
 	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
 	Balance   int64  `json:"balance"`
 	Currency  string `json:"currency"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.AccountID, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.AccountID,
+		arg.Owner,
+		arg.Balance,
+		arg.Currency,
+	)
 	var i Account
 	err := row.Scan(
 		&i.AccountID,
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Owner,
+		&i.HeldBalance,
 	)
 	return i, err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT account_id, balance, currency, created_at FROM accounts
+SELECT account_id, balance, currency, created_at, owner, held_balance FROM accounts
 WHERE account_id = $1
 LIMIT 1
 `
@@ -47,24 +83,148 @@ func (q *Queries) GetAccount(ctx context.Context, accountID int64) (Account, err
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Owner,
+		&i.HeldBalance,
 	)
 	return i, err
 }
 
+const getAccountForUpdate = `-- name: GetAccountForUpdate :one
+SELECT account_id, balance, currency, created_at, owner, held_balance FROM accounts
+WHERE account_id = $1
+LIMIT 1
+FOR NO KEY UPDATE
+`
+
+// Locks the row until the end of the transaction without blocking inserts that reference it.
+func (q *Queries) GetAccountForUpdate(ctx context.Context, accountID int64) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountForUpdate, accountID)
+	var i Account
+	err := row.Scan(
+		&i.AccountID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.Owner,
+		&i.HeldBalance,
+	)
+	return i, err
+}
+
+const getAccountsForUpdate = `-- name: GetAccountsForUpdate :many
+SELECT account_id, balance, currency, created_at, owner, held_balance FROM accounts
+WHERE account_id = ANY($1::bigint[])
+ORDER BY account_id
+FOR NO KEY UPDATE
+`
+
+// Locks the rows in account_id order, so transactions locking overlapping sets of accounts can't deadlock.
+func (q *Queries) GetAccountsForUpdate(ctx context.Context, accountIds []int64) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getAccountsForUpdate, accountIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.Owner,
+			&i.HeldBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const hasAccountInCurrency = `-- name: HasAccountInCurrency :one
+SELECT EXISTS (
+  SELECT 1 FROM accounts
+  WHERE owner = $1 AND currency = $2
+)
+`
+
+type HasAccountInCurrencyParams struct {
+	Owner    string `json:"owner"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) HasAccountInCurrency(ctx context.Context, arg HasAccountInCurrencyParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasAccountInCurrency, arg.Owner, arg.Currency)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const listAccounts = `-- name: ListAccounts :many
-SELECT account_id, balance, currency, created_at FROM accounts
+SELECT account_id, balance, currency, created_at, owner, held_balance FROM accounts
+WHERE owner = $1
+  AND account_id > $2
+  AND ($3::varchar IS NULL OR currency = $3)
+  AND ($4::bigint IS NULL OR balance >= $4)
 ORDER BY account_id
-LIMIT $1
-OFFSET $2
+LIMIT $5
 `
 
 type ListAccountsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Owner          string      `json:"owner"`
+	AfterAccountID int64       `json:"after_account_id"`
+	Currency       pgtype.Text `json:"currency"`
+	MinBalance     pgtype.Int8 `json:"min_balance"`
+	PageLimit      int32       `json:"page_limit"`
 }
 
+// Keyset pagination over account_id; pass 0 as after_account_id for the first page.
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.Query(ctx, listAccounts, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts,
+		arg.Owner,
+		arg.AfterAccountID,
+		arg.Currency,
+		arg.MinBalance,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.Owner,
+			&i.HeldBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountsByID = `-- name: ListAccountsByID :many
+SELECT account_id, balance, currency, created_at, owner, held_balance FROM accounts
+WHERE account_id = ANY($1::bigint[])
+ORDER BY account_id
+`
+
+func (q *Queries) ListAccountsByID(ctx context.Context, accountIds []int64) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsByID, accountIds)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +237,8 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.Owner,
+			&i.HeldBalance,
 		); err != nil {
 			return nil, err
 		}
@@ -92,7 +254,7 @@ const updateBalance = `-- name: UpdateBalance :one
 UPDATE accounts
 SET balance = balance + $1
 WHERE account_id = $2
-RETURNING account_id, balance, currency, created_at
+RETURNING account_id, balance, currency, created_at, owner, held_balance
 `
 
 type UpdateBalanceParams struct {
@@ -108,6 +270,8 @@ func (q *Queries) UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.Owner,
+		&i.HeldBalance,
 	)
 	return i, err
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,8 +15,11 @@ func TestCreateAccount(t *testing.T) {
 }
 
 func createRandomAccount(t *testing.T) Account {
+	user := createRandomUser(t)
+
 	arg := CreateAccountParams{
 		AccountID: util.RandomAccountID(),
+		Owner:     user.Username,
 		Balance:   util.RandomMoney(),
 		Currency:  util.RandomCurrency(),
 	}
@@ -25,6 +29,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.NotEmpty(t, account)
 
 	require.Equal(t, arg.AccountID, account.AccountID)
+	require.Equal(t, arg.Owner, account.Owner)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
 	require.NotZero(t, account.CreatedAt)
@@ -40,6 +45,7 @@ func TestGetAccount(t *testing.T) {
 	require.NotEmpty(t, account2)
 
 	require.Equal(t, account1.AccountID, account2.AccountID)
+	require.Equal(t, account1.Owner, account2.Owner)
 	require.Equal(t, account1.Balance, account2.Balance)
 	require.Equal(t, account1.Currency, account2.Currency)
 	require.WithinDuration(t, account1.CreatedAt.Time, account2.CreatedAt.Time, time.Second)
@@ -98,51 +104,70 @@ func TestUpdateBalanceSubtract(t *testing.T) {
 }
 
 func TestListAccounts(t *testing.T) {
-	var createdAccounts []Account
-	for i := 0; i < 10; i++ {
-		account := createRandomAccount(t)
-		createdAccounts = append(createdAccounts, account)
+	user := createRandomUser(t)
+
+	var created []Account
+	for _, currency := range []string{util.USD, util.EUR, util.CAD} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     user.Username,
+			Balance:   util.RandomMoney(),
+			Currency:  currency,
+		})
+		require.NoError(t, err)
+		created = append(created, account)
 	}
 
 	arg := ListAccountsParams{
-		Limit:  5,
-		Offset: 0,
+		Owner:     user.Username,
+		PageLimit: 2,
 	}
 
-	accounts, err := testQueries.ListAccounts(context.Background(), arg)
+	page1, err := testQueries.ListAccounts(context.Background(), arg)
 	require.NoError(t, err)
-	require.Len(t, accounts, 5)
+	require.Len(t, page1, 2)
 
-	for _, account := range accounts {
-		require.NotEmpty(t, account)
-		require.NotZero(t, account.AccountID)
-		require.NotEmpty(t, account.Currency)
-		require.NotZero(t, account.CreatedAt)
+	arg.AfterAccountID = page1[len(page1)-1].AccountID
+	page2, err := testQueries.ListAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 1)
+
+	accounts := append(page1, page2...)
+	for i, account := range accounts {
+		require.Equal(t, user.Username, account.Owner)
+		if i > 0 {
+			require.Greater(t, account.AccountID, accounts[i-1].AccountID)
+		}
 	}
+	require.ElementsMatch(t, created, accounts)
 }
 
-func TestListAccountsWithOffset(t *testing.T) {
-	for i := 0; i < 15; i++ {
-		createRandomAccount(t)
-	}
+func TestListAccountsFilters(t *testing.T) {
+	account := createRandomAccount(t)
 
 	arg := ListAccountsParams{
-		Limit:  5,
-		Offset: 5,
+		Owner:      account.Owner,
+		Currency:   pgtype.Text{String: account.Currency, Valid: true},
+		MinBalance: pgtype.Int8{Int64: account.Balance, Valid: true},
+		PageLimit:  5,
 	}
 
 	accounts, err := testQueries.ListAccounts(context.Background(), arg)
 	require.NoError(t, err)
-	require.LessOrEqual(t, len(accounts), 5)
+	require.Equal(t, []Account{account}, accounts)
 
-	for _, account := range accounts {
-		require.NotEmpty(t, account)
-	}
+	arg.MinBalance = pgtype.Int8{Int64: account.Balance + 1, Valid: true}
+	accounts, err = testQueries.ListAccounts(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, accounts)
 }
 
 func TestCreateAccountZeroBalance(t *testing.T) {
+	user := createRandomUser(t)
+
 	arg := CreateAccountParams{
 		AccountID: util.RandomAccountID(),
+		Owner:     user.Username,
 		Balance:   0,
 		Currency:  util.RandomCurrency(),
 	}
@@ -154,8 +179,11 @@ func TestCreateAccountZeroBalance(t *testing.T) {
 }
 
 func TestCreateAccountNegativeBalanceFails(t *testing.T) {
+	user := createRandomUser(t)
+
 	arg := CreateAccountParams{
 		AccountID: util.RandomAccountID(),
+		Owner:     user.Username,
 		Balance:   -util.RandomMoney(),
 		Currency:  util.RandomCurrency(),
 	}
@@ -165,3 +193,34 @@ func TestCreateAccountNegativeBalanceFails(t *testing.T) {
 	require.Empty(t, account)
 	require.Contains(t, err.Error(), "accounts_balance_check")
 }
+
+func TestCreateAccountSameCurrency(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	arg := CreateAccountParams{
+		AccountID: util.RandomAccountID(),
+		Owner:     account1.Owner,
+		Balance:   util.RandomMoney(),
+		Currency:  account1.Currency,
+	}
+
+	// one account per currency is opt-in, so the schema allows a second one
+	account2, err := testQueries.CreateAccount(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, account1.Owner, account2.Owner)
+	require.Equal(t, account1.Currency, account2.Currency)
+}
+
+func TestCreateAccountUnknownOwnerFails(t *testing.T) {
+	arg := CreateAccountParams{
+		AccountID: util.RandomAccountID(),
+		Owner:     util.RandomOwner(),
+		Balance:   util.RandomMoney(),
+		Currency:  util.RandomCurrency(),
+	}
+
+	account, err := testQueries.CreateAccount(context.Background(), arg)
+	require.Error(t, err)
+	require.Empty(t, account)
+	require.Contains(t, err.Error(), "fk_account_owner")
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"math/big"
+
+	"github.com/chandiniv1/transfers-system/fx"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Conversion makes a transfer credit the destination account in its own currency
+type Conversion struct {
+	// Rate is the applied rate, spread included
+	Rate              fx.Rate `json:"rate"`
+	DestinationAmount int64   `json:"destination_amount"`
+}
+
+// AppliedRate returns the exchange rate of a converted transaction, or false if it moved a single currency
+func (transaction Transaction) AppliedRate() (fx.Rate, bool) {
+	if !transaction.ExchangeRate.Valid {
+		return 0, false
+	}
+
+	rate, err := fx.RateFromDecimal(transaction.ExchangeRate.Int, transaction.ExchangeRate.Exp)
+	if err != nil {
+		return 0, false
+	}
+
+	return rate, true
+}
+
+// setConversion records a conversion on the transaction to create
+func (arg *CreateTransactionParams) setConversion(rate fx.Rate, destinationAmount int64) {
+	unscaled, exp := rate.Decimal()
+
+	arg.DestinationAmount = pgtype.Int8{Int64: destinationAmount, Valid: true}
+	arg.ExchangeRate = pgtype.Numeric{Int: unscaled, Exp: exp, Valid: true}
+}
+
+// prorate returns part/whole of total, rounded down
+func prorate(total, part, whole int64) int64 {
+	result := new(big.Int).Mul(big.NewInt(total), big.NewInt(part))
+	return result.Quo(result, big.NewInt(whole)).Int64()
+}
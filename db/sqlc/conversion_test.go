@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chandiniv1/transfers-system/fx"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomLedgerAccountIn(t *testing.T, currency string, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     user.Username,
+			Balance:   balance,
+			Currency:  currency,
+		},
+	})
+	require.NoError(t, err)
+
+	return account
+}
+
+func TestTransferTxConversion(t *testing.T) {
+	usdAccount := createRandomLedgerAccountIn(t, util.USD, 1000)
+	eurAccount := createRandomLedgerAccountIn(t, util.EUR, 0)
+	rate := fx.Rate(91_540_000)
+
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: usdAccount.AccountID,
+		ToAccountID:   eurAccount.AccountID,
+		Amount:        100,
+		Conversion:    &Conversion{Rate: rate, DestinationAmount: 91},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int64(100), result.Transaction.Amount)
+	require.Equal(t, int64(91), result.Transaction.DestinationAmount.Int64)
+	applied, ok := result.Transaction.AppliedRate()
+	require.True(t, ok)
+	require.Equal(t, rate, applied)
+
+	require.Equal(t, int64(900), result.FromAccount.Balance)
+	require.Equal(t, int64(91), result.ToAccount.Balance)
+	require.Equal(t, int64(-100), result.FromEntry.Amount)
+	require.Equal(t, int64(91), result.ToEntry.Amount)
+
+	transaction, err := testQueries.GetTransaction(context.Background(), result.Transaction.ID)
+	require.NoError(t, err)
+	applied, ok = transaction.AppliedRate()
+	require.True(t, ok)
+	require.Equal(t, rate, applied)
+
+	requireLedgerConsistent(t, usdAccount.AccountID, eurAccount.AccountID)
+}
+
+func TestReverseTransferTxConversion(t *testing.T) {
+	usdAccount := createRandomLedgerAccountIn(t, util.USD, 1000)
+	eurAccount := createRandomLedgerAccountIn(t, util.EUR, 0)
+
+	original, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: usdAccount.AccountID,
+		ToAccountID:   eurAccount.AccountID,
+		Amount:        100,
+		Conversion:    &Conversion{Rate: 91_540_000, DestinationAmount: 91},
+	})
+	require.NoError(t, err)
+
+	// refunds are in dollars and give back the matching share of the euros credited
+	result, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.Transaction.ID,
+		Amount:        33,
+		Reason:        "partial refund",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(30), result.Reversal.Transaction.Amount)
+	require.Equal(t, int64(33), result.Reversal.Transaction.DestinationAmount.Int64)
+
+	result, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.Transaction.ID,
+		Reason:        "rest of the refund",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(61), result.Reversal.Transaction.Amount)
+	require.Equal(t, int64(67), result.Reversal.Transaction.DestinationAmount.Int64)
+
+	require.Zero(t, result.Reversal.FromAccount.Balance)
+	require.Equal(t, int64(1000), result.Reversal.ToAccount.Balance)
+
+	requireLedgerConsistent(t, usdAccount.AccountID, eurAccount.AccountID)
+}
+
+func TestReverseTransferTxConversionTooSmall(t *testing.T) {
+	usdAccount := createRandomLedgerAccountIn(t, util.USD, 1000)
+	eurAccount := createRandomLedgerAccountIn(t, util.EUR, 0)
+
+	original, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: usdAccount.AccountID,
+		ToAccountID:   eurAccount.AccountID,
+		Amount:        100,
+		Conversion:    &Conversion{Rate: 91_540_000, DestinationAmount: 91},
+	})
+	require.NoError(t, err)
+
+	// a cent of the 100 prorates to none of the 91 euro cents credited
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.Transaction.ID,
+		Amount:        1,
+		Reason:        "tiny refund",
+	})
+	require.ErrorIs(t, err, ErrReversalAmountTooSmall)
+
+	transaction, err := testStore.GetTransaction(context.Background(), original.Transaction.ID)
+	require.NoError(t, err)
+	require.Zero(t, transaction.ReversedAmount)
+
+	requireLedgerConsistent(t, usdAccount.AccountID, eurAccount.AccountID)
+}
+
+func TestProrate(t *testing.T) {
+	require.Equal(t, int64(30), prorate(91, 33, 100))
+	require.Equal(t, int64(91), prorate(91, 100, 100))
+	require.Zero(t, prorate(91, 0, 100))
+}
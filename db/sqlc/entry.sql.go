@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: entry.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createEntry = `-- name: CreateEntry :one
+INSERT INTO entries (
+  account_id,
+  transaction_id,
+  amount,
+  balance
+)
+VALUES ($1, $2, $3, $4)
+RETURNING id, account_id, transaction_id, amount, balance, created_at
+`
+
+type CreateEntryParams struct {
+	AccountID     int64       `json:"account_id"`
+	TransactionID pgtype.Int8 `json:"transaction_id"`
+	Amount        int64       `json:"amount"`
+	Balance       int64       `json:"balance"`
+}
+
+func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	row := q.db.QueryRow(ctx, createEntry,
+		arg.AccountID,
+		arg.TransactionID,
+		arg.Amount,
+		arg.Balance,
+	)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Balance,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getEntry = `-- name: GetEntry :one
+SELECT id, account_id, transaction_id, amount, balance, created_at FROM entries
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
+	row := q.db.QueryRow(ctx, getEntry, id)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.TransactionID,
+		&i.Amount,
+		&i.Balance,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listBalanceMismatches = `-- name: ListBalanceMismatches :many
+SELECT a.account_id, a.balance, COALESCE(SUM(e.amount), 0)::bigint AS entries_total
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.account_id
+GROUP BY a.account_id, a.balance
+HAVING a.balance <> COALESCE(SUM(e.amount), 0)
+`
+
+type ListBalanceMismatchesRow struct {
+	AccountID    int64 `json:"account_id"`
+	Balance      int64 `json:"balance"`
+	EntriesTotal int64 `json:"entries_total"`
+}
+
+// Accounts whose balance differs from the sum of their entries; empty when the ledger is consistent.
+func (q *Queries) ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error) {
+	rows, err := q.db.Query(ctx, listBalanceMismatches)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBalanceMismatchesRow
+	for rows.Next() {
+		var i ListBalanceMismatchesRow
+		if err := rows.Scan(&i.AccountID, &i.Balance, &i.EntriesTotal); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntries = `-- name: ListEntries :many
+SELECT id, account_id, transaction_id, amount, balance, created_at FROM entries
+WHERE account_id = $1
+  AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListEntriesParams struct {
+	AccountID int64 `json:"account_id"`
+	AfterID   int64 `json:"after_id"`
+	PageLimit int32 `json:"page_limit"`
+}
+
+// Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listEntries, arg.AccountID, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Entry
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.TransactionID,
+			&i.Amount,
+			&i.Balance,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
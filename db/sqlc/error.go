@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes translated into the sentinel errors below
+const (
+	ForeignKeyViolation  = "23503"
+	UniqueViolation      = "23505"
+	CheckViolation       = "23514"
+	NumericOutOfRange    = "22003"
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// Check constraints on accounts whose violation means the account can't cover the amount
+const (
+	// balanceCheckConstraint is the name Postgres gives to CHECK (balance >= 0)
+	balanceCheckConstraint = "accounts_balance_check"
+	// availableBalanceCheckConstraint keeps active holds covered by the balance
+	availableBalanceCheckConstraint = "accounts_available_balance_check"
+)
+
+// Errors returned by the Store in place of the driver errors they translate.
+// The original pgx error stays in the chain, so errors.As still finds a *pgconn.PgError.
+var (
+	ErrRecordNotFound      = errors.New("record not found")
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check violation")
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	// ErrAmountOutOfRange is returned when a balance or amount would overflow its bigint column
+	ErrAmountOutOfRange = errors.New("amount out of range")
+
+	// ErrHoldNotActive is returned when a hold was already captured, voided or has expired
+	ErrHoldNotActive = errors.New("hold is not active")
+	// ErrHoldAmountExceeded is returned when a capture asks for more than the held amount
+	ErrHoldAmountExceeded = errors.New("capture amount exceeds the held amount")
+
+	// ErrReversalNotAllowed is returned when asked to reverse a transaction that is itself a reversal
+	ErrReversalNotAllowed = errors.New("a reversal can't be reversed")
+	// ErrReversalAmountExceeded is returned when a reversal would refund more than is left of the transaction
+	ErrReversalAmountExceeded = errors.New("reversal exceeds the amount left to reverse")
+	// ErrReversalAmountTooSmall is returned when a partial reversal of a conversion would give back
+	// nothing of the currency the destination account was credited in
+	ErrReversalAmountTooSmall = errors.New("reversal amount is too small to convert back")
+
+	// ErrScheduledTransferNotPending is returned when a scheduled transfer was already executed, failed or canceled
+	ErrScheduledTransferNotPending = errors.New("scheduled transfer is not pending")
+	// ErrStandingOrderNotActive is returned when a standing order was already completed or canceled
+	ErrStandingOrderNotActive = errors.New("standing order is not active")
+
+	// ErrSerializationFailure is returned when Postgres aborted the transaction because of a concurrent
+	// one, either as a serialization failure or a deadlock. The transaction can be run again.
+	ErrSerializationFailure = errors.New("serialization failure")
+)
+
+// errorProblems gives the problem code clients are told each sentinel error by, both by the HTTP API
+// and the gRPC gateway. The status of the response follows from the code.
+var errorProblems = []struct {
+	err  error
+	code string
+}{
+	{ErrRecordNotFound, util.ProblemNotFound},
+	{ErrUniqueViolation, util.ProblemAlreadyExists},
+	{ErrForeignKeyViolation, util.ProblemInvalidReference},
+	{ErrInsufficientFunds, util.ProblemInsufficientFunds},
+	{ErrCheckViolation, util.ProblemInvalidRequest},
+	{ErrAmountOutOfRange, util.ProblemInvalidRequest},
+	{ErrHoldNotActive, util.ProblemHoldNotActive},
+	{ErrHoldAmountExceeded, util.ProblemInvalidRequest},
+	{ErrReversalNotAllowed, util.ProblemReversalNotAllowed},
+	{ErrReversalAmountExceeded, util.ProblemReversalAmountExceeded},
+	{ErrReversalAmountTooSmall, util.ProblemInvalidRequest},
+	{ErrScheduledTransferNotPending, util.ProblemScheduledTransferNotPending},
+	{ErrStandingOrderNotActive, util.ProblemStandingOrderNotActive},
+	{ErrSerializationFailure, util.ProblemConflict},
+}
+
+// ErrorProblemCode returns the problem code of an error returned by the store,
+// or an empty string if it isn't one of the sentinel errors above.
+func ErrorProblemCode(err error) string {
+	for _, p := range errorProblems {
+		if errors.Is(err, p.err) {
+			return p.code
+		}
+	}
+	return ""
+}
+
+// InsufficientFundsError is returned by TransferTx and CreateHoldTx when the available balance
+// of the account doesn't cover the amount.
+// It matches ErrInsufficientFunds with errors.Is.
+type InsufficientFundsError struct {
+	AccountID int64
+	Available int64
+	Requested int64
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("%s in account %d: available %d, requested %d", ErrInsufficientFunds, e.AccountID, e.Available, e.Requested)
+}
+
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds
+}
+
+// translateError wraps a pgx error with the sentinel error it corresponds to
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", ErrRecordNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case UniqueViolation:
+		return fmt.Errorf("%w: %w", ErrUniqueViolation, err)
+	case ForeignKeyViolation:
+		return fmt.Errorf("%w: %w", ErrForeignKeyViolation, err)
+	case CheckViolation:
+		if pgErr.ConstraintName == balanceCheckConstraint || pgErr.ConstraintName == availableBalanceCheckConstraint {
+			return fmt.Errorf("%w: %w", ErrInsufficientFunds, err)
+		}
+		return fmt.Errorf("%w: %w", ErrCheckViolation, err)
+	case NumericOutOfRange:
+		return fmt.Errorf("%w: %w", ErrAmountOutOfRange, err)
+	case SerializationFailure, DeadlockDetected:
+		return fmt.Errorf("%w: %w", ErrSerializationFailure, err)
+	}
+
+	return err
+}
+
+// errorTranslatingDBTX runs queries on db and translates the errors they return
+type errorTranslatingDBTX struct {
+	db DBTX
+}
+
+func (d errorTranslatingDBTX) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	tag, err := d.db.Exec(ctx, sql, args...)
+	return tag, translateError(err)
+}
+
+func (d errorTranslatingDBTX) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	rows, err := d.db.Query(ctx, sql, args...)
+	if err != nil {
+		return rows, translateError(err)
+	}
+	return errorTranslatingRows{rows}, nil
+}
+
+func (d errorTranslatingDBTX) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return errorTranslatingRow{d.db.QueryRow(ctx, sql, args...)}
+}
+
+type errorTranslatingRow struct {
+	pgx.Row
+}
+
+func (r errorTranslatingRow) Scan(dest ...any) error {
+	return translateError(r.Row.Scan(dest...))
+}
+
+type errorTranslatingRows struct {
+	pgx.Rows
+}
+
+func (r errorTranslatingRows) Scan(dest ...any) error {
+	return translateError(r.Rows.Scan(dest...))
+}
+
+func (r errorTranslatingRows) Err() error {
+	return translateError(r.Rows.Err())
+}
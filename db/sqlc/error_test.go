@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{
+			name:     "No Rows",
+			err:      pgx.ErrNoRows,
+			sentinel: ErrRecordNotFound,
+		},
+		{
+			name:     "Wrapped No Rows",
+			err:      fmt.Errorf("failed to get account: %w", pgx.ErrNoRows),
+			sentinel: ErrRecordNotFound,
+		},
+		{
+			name:     "Unique Violation",
+			err:      &pgconn.PgError{Code: UniqueViolation, ConstraintName: "accounts_pkey"},
+			sentinel: ErrUniqueViolation,
+		},
+		{
+			name:     "Foreign Key Violation",
+			err:      &pgconn.PgError{Code: ForeignKeyViolation, ConstraintName: "fk_account_owner"},
+			sentinel: ErrForeignKeyViolation,
+		},
+		{
+			name:     "Balance Check Violation",
+			err:      &pgconn.PgError{Code: CheckViolation, ConstraintName: balanceCheckConstraint},
+			sentinel: ErrInsufficientFunds,
+		},
+		{
+			name:     "Available Balance Check Violation",
+			err:      &pgconn.PgError{Code: CheckViolation, ConstraintName: availableBalanceCheckConstraint},
+			sentinel: ErrInsufficientFunds,
+		},
+		{
+			name:     "Other Check Violation",
+			err:      &pgconn.PgError{Code: CheckViolation, ConstraintName: "check_source_not_eq_destination"},
+			sentinel: ErrCheckViolation,
+		},
+		{
+			name:     "Numeric Out Of Range",
+			err:      &pgconn.PgError{Code: NumericOutOfRange},
+			sentinel: ErrAmountOutOfRange,
+		},
+		{
+			name:     "Serialization Failure",
+			err:      &pgconn.PgError{Code: SerializationFailure},
+			sentinel: ErrSerializationFailure,
+		},
+		{
+			name:     "Deadlock Detected",
+			err:      &pgconn.PgError{Code: DeadlockDetected},
+			sentinel: ErrSerializationFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := translateError(tc.err)
+			require.ErrorIs(t, err, tc.sentinel)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	require.NoError(t, translateError(nil))
+
+	other := &pgconn.PgError{Code: "57014"}
+	require.Equal(t, error(other), translateError(other))
+
+	plain := errors.New("connection refused")
+	require.Equal(t, plain, translateError(plain))
+}
+
+func TestStoreTranslatesErrors(t *testing.T) {
+	account := createRandomAccount(t)
+
+	_, err := testStore.GetAccount(context.Background(), util.RandomAccountID()+1_000_000)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = testStore.CreateAccount(context.Background(), CreateAccountParams{
+		AccountID: account.AccountID,
+		Owner:     account.Owner,
+		Balance:   util.RandomMoney(),
+		Currency:  account.Currency,
+	})
+	require.ErrorIs(t, err, ErrUniqueViolation)
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+	require.Equal(t, "accounts_pkey", pgErr.ConstraintName)
+
+	_, err = testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     util.RandomOwner(),
+			Balance:   util.RandomMoney(),
+			Currency:  util.RandomCurrency(),
+		},
+	})
+	require.ErrorIs(t, err, ErrForeignKeyViolation)
+
+	_, err = testStore.UpdateBalance(context.Background(), UpdateBalanceParams{
+		AccountID: account.AccountID,
+		Amount:    -(account.Balance + 1),
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	_, err = testStore.CreateTransaction(context.Background(), CreateTransactionParams{
+		SourceAccountID:      account.AccountID,
+		DestinationAccountID: account.AccountID,
+		Amount:               1,
+	})
+	require.ErrorIs(t, err, ErrCheckViolation)
+}
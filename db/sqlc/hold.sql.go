@@ -0,0 +1,172 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: hold.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO holds (
+  account_id,
+  to_account_id,
+  amount,
+  expires_at
+)
+VALUES ($1, $2, $3, $4)
+RETURNING id, account_id, to_account_id, amount, status, captured_amount, transaction_id, expires_at, created_at, resolved_at
+`
+
+type CreateHoldParams struct {
+	AccountID   int64              `json:"account_id"`
+	ToAccountID int64              `json:"to_account_id"`
+	Amount      int64              `json:"amount"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, createHold,
+		arg.AccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransactionID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const getHold = `-- name: GetHold :one
+SELECT id, account_id, to_account_id, amount, status, captured_amount, transaction_id, expires_at, created_at, resolved_at FROM holds
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetHold(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHold, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransactionID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, account_id, to_account_id, amount, status, captured_amount, transaction_id, expires_at, created_at, resolved_at FROM holds
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	row := q.db.QueryRow(ctx, getHoldForUpdate, id)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransactionID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const listExpiredHolds = `-- name: ListExpiredHolds :many
+SELECT id FROM holds
+WHERE status = 'active'
+  AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1
+`
+
+// Active holds past their deadline, oldest first.
+func (q *Queries) ListExpiredHolds(ctx context.Context, limit int32) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listExpiredHolds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resolveHold = `-- name: ResolveHold :one
+UPDATE holds
+SET status = $1,
+    captured_amount = $2,
+    transaction_id = $3,
+    resolved_at = now()
+WHERE id = $4
+RETURNING id, account_id, to_account_id, amount, status, captured_amount, transaction_id, expires_at, created_at, resolved_at
+`
+
+type ResolveHoldParams struct {
+	Status         string      `json:"status"`
+	CapturedAmount pgtype.Int8 `json:"captured_amount"`
+	TransactionID  pgtype.Int8 `json:"transaction_id"`
+	ID             int64       `json:"id"`
+}
+
+// Moves an active hold to its final status; captured_amount and transaction_id are only set on capture.
+func (q *Queries) ResolveHold(ctx context.Context, arg ResolveHoldParams) (Hold, error) {
+	row := q.db.QueryRow(ctx, resolveHold,
+		arg.Status,
+		arg.CapturedAmount,
+		arg.TransactionID,
+		arg.ID,
+	)
+	var i Hold
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.CapturedAmount,
+		&i.TransactionID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomIdempotencyKey(t *testing.T, expiresAt time.Time) IdempotencyKey {
+	user := createRandomUser(t)
+
+	arg := CreateIdempotencyKeyParams{
+		Username:     user.Username,
+		Key:          util.RandomString(16),
+		RequestHash:  util.RandomString(64),
+		ResponseBody: []byte(`{"ok":true}`),
+		ExpiresAt:    pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+
+	key, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, key.Username)
+	require.Equal(t, arg.Key, key.Key)
+	require.Equal(t, arg.RequestHash, key.RequestHash)
+	require.Equal(t, arg.ResponseBody, key.ResponseBody)
+	require.NotZero(t, key.CreatedAt)
+
+	return key
+}
+
+func TestCreateIdempotencyKey(t *testing.T) {
+	createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+}
+
+func TestGetIdempotencyKey(t *testing.T) {
+	key1 := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+
+	key2, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key1.Username,
+		Key:      key1.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, key1.RequestHash, key2.RequestHash)
+	require.Equal(t, key1.ResponseBody, key2.ResponseBody)
+}
+
+func TestGetExpiredIdempotencyKey(t *testing.T) {
+	key := createRandomIdempotencyKey(t, time.Now().Add(-time.Minute))
+
+	_, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: key.Username,
+		Key:      key.Key,
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestCreateIdempotencyKeyLiveKeyConflict(t *testing.T) {
+	key := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+
+	_, err := testQueries.CreateIdempotencyKey(context.Background(), CreateIdempotencyKeyParams{
+		Username:     key.Username,
+		Key:          key.Key,
+		RequestHash:  util.RandomString(64),
+		ResponseBody: []byte(`{}`),
+		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestCreateIdempotencyKeyTakesOverExpiredKey(t *testing.T) {
+	key1 := createRandomIdempotencyKey(t, time.Now().Add(-time.Minute))
+
+	arg := CreateIdempotencyKeyParams{
+		Username:     key1.Username,
+		Key:          key1.Key,
+		RequestHash:  util.RandomString(64),
+		ResponseBody: []byte(`{"new":true}`),
+		ExpiresAt:    pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	key2, err := testQueries.CreateIdempotencyKey(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RequestHash, key2.RequestHash)
+	require.Equal(t, arg.ResponseBody, key2.ResponseBody)
+}
+
+func TestDeleteExpiredIdempotencyKeys(t *testing.T) {
+	expired := createRandomIdempotencyKey(t, time.Now().Add(-time.Minute))
+	live := createRandomIdempotencyKey(t, time.Now().Add(time.Hour))
+
+	// other tests leave expired keys behind, so delete until none is left
+	for {
+		deleted, err := testQueries.DeleteExpiredIdempotencyKeys(context.Background(), 100)
+		require.NoError(t, err)
+		if deleted < 100 {
+			break
+		}
+	}
+
+	var count int
+	err := testDB.QueryRow(context.Background(),
+		"SELECT count(*) FROM idempotency_keys WHERE (username, key) IN (($1, $2), ($3, $4))",
+		expired.Username, expired.Key, live.Username, live.Key).Scan(&count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
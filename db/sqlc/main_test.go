@@ -11,6 +11,7 @@ import (
 )
 
 var testQueries *Queries
+var testStore Store
 var testDB *pgxpool.Pool
 
 func TestMain(m *testing.M) {
@@ -27,7 +28,13 @@ func TestMain(m *testing.M) {
 	}
 	defer testDB.Close()
 
+	txConfig, err := NewTxConfig(config)
+	if err != nil {
+		log.Fatal("invalid transaction config:", err)
+	}
+
 	testQueries = New(testDB)
+	testStore = NewStore(testDB, txConfig)
 
 	os.Exit(m.Run())
 }
@@ -5,20 +5,149 @@
 package db
 
 import (
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Account struct {
-	AccountID int64              `json:"account_id"`
-	Balance   int64              `json:"balance"`
-	Currency  string             `json:"currency"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	AccountID   int64              `json:"account_id"`
+	Balance     int64              `json:"balance"`
+	Currency    string             `json:"currency"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	Owner       string             `json:"owner"`
+	HeldBalance int64              `json:"held_balance"`
+}
+
+type Entry struct {
+	ID            int64              `json:"id"`
+	AccountID     int64              `json:"account_id"`
+	TransactionID pgtype.Int8        `json:"transaction_id"`
+	Amount        int64              `json:"amount"`
+	Balance       int64              `json:"balance"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+type Hold struct {
+	ID             int64              `json:"id"`
+	AccountID      int64              `json:"account_id"`
+	ToAccountID    int64              `json:"to_account_id"`
+	Amount         int64              `json:"amount"`
+	Status         string             `json:"status"`
+	CapturedAmount pgtype.Int8        `json:"captured_amount"`
+	TransactionID  pgtype.Int8        `json:"transaction_id"`
+	ExpiresAt      pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt     pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type IdempotencyKey struct {
+	Username     string             `json:"username"`
+	Key          string             `json:"key"`
+	RequestHash  string             `json:"request_hash"`
+	ResponseBody []byte             `json:"response_body"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+}
+
+type ScheduledTransfer struct {
+	ID            int64              `json:"id"`
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        int64              `json:"amount"`
+	ExecuteAt     pgtype.Timestamptz `json:"execute_at"`
+	Status        string             `json:"status"`
+	TransactionID pgtype.Int8        `json:"transaction_id"`
+	FailureReason pgtype.Text        `json:"failure_reason"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt    pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type Session struct {
+	ID           uuid.UUID          `json:"id"`
+	Username     string             `json:"username"`
+	RefreshToken string             `json:"refresh_token"`
+	UserAgent    string             `json:"user_agent"`
+	ClientIp     string             `json:"client_ip"`
+	IsBlocked    bool               `json:"is_blocked"`
+	ExpiresAt    pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt    pgtype.Timestamptz `json:"created_at"`
+}
+
+type StandingOrder struct {
+	ID                      int64              `json:"id"`
+	FromAccountID           int64              `json:"from_account_id"`
+	ToAccountID             int64              `json:"to_account_id"`
+	Amount                  int64              `json:"amount"`
+	Schedule                string             `json:"schedule"`
+	StartAt                 pgtype.Timestamptz `json:"start_at"`
+	EndAt                   pgtype.Timestamptz `json:"end_at"`
+	MaxOccurrences          pgtype.Int4        `json:"max_occurrences"`
+	InsufficientFundsPolicy string             `json:"insufficient_funds_policy"`
+	Status                  string             `json:"status"`
+	NextRunAt               pgtype.Timestamptz `json:"next_run_at"`
+	RetryAt                 pgtype.Timestamptz `json:"retry_at"`
+	Retries                 int32              `json:"retries"`
+	Occurrences             int32              `json:"occurrences"`
+	CreatedAt               pgtype.Timestamptz `json:"created_at"`
+	EndedAt                 pgtype.Timestamptz `json:"ended_at"`
+}
+
+type StandingOrderExecution struct {
+	ID              int64              `json:"id"`
+	StandingOrderID int64              `json:"standing_order_id"`
+	OccurrenceAt    pgtype.Timestamptz `json:"occurrence_at"`
+	Status          string             `json:"status"`
+	TransactionID   pgtype.Int8        `json:"transaction_id"`
+	FailureReason   pgtype.Text        `json:"failure_reason"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
 }
 
 type Transaction struct {
-	ID                   int64              `json:"id"`
-	SourceAccountID      int64              `json:"source_account_id"`
-	DestinationAccountID int64              `json:"destination_account_id"`
-	Amount               int64              `json:"amount"`
-	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	ID                    int64              `json:"id"`
+	SourceAccountID       int64              `json:"source_account_id"`
+	DestinationAccountID  pgtype.Int8        `json:"destination_account_id"`
+	Amount                int64              `json:"amount"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+	OriginalTransactionID pgtype.Int8        `json:"original_transaction_id"`
+	Reason                pgtype.Text        `json:"reason"`
+	ReversedAmount        int64              `json:"reversed_amount"`
+	DestinationAmount     pgtype.Int8        `json:"destination_amount"`
+	ExchangeRate          pgtype.Numeric     `json:"exchange_rate"`
+}
+
+type TransactionLeg struct {
+	TransactionID int64  `json:"transaction_id"`
+	Position      int32  `json:"position"`
+	AccountID     int64  `json:"account_id"`
+	Amount        int64  `json:"amount"`
+	Memo          string `json:"memo"`
+}
+
+type TransferBatch struct {
+	ID          int64              `json:"id"`
+	Owner       string             `json:"owner"`
+	Mode        string             `json:"mode"`
+	Status      string             `json:"status"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+type TransferBatchItem struct {
+	BatchID       int64       `json:"batch_id"`
+	Position      int32       `json:"position"`
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        int64       `json:"amount"`
+	Status        string      `json:"status"`
+	TransactionID pgtype.Int8 `json:"transaction_id"`
+	Error         pgtype.Text `json:"error"`
+}
+
+type User struct {
+	Username          string             `json:"username"`
+	HashedPassword    string             `json:"hashed_password"`
+	FullName          string             `json:"full_name"`
+	Email             string             `json:"email"`
+	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
 }
@@ -6,14 +6,90 @@ package db
 
 import (
 	"context"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddHeldBalance(ctx context.Context, arg AddHeldBalanceParams) (Account, error)
+	AddReversedAmount(ctx context.Context, arg AddReversedAmountParams) (Transaction, error)
+	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	// Locks the oldest pending transfer that is due, skipping those another worker is executing.
+	ClaimDueScheduledTransfer(ctx context.Context) (ScheduledTransfer, error)
+	// Locks the active order whose occurrence or retry is most overdue, skipping those another worker is running.
+	ClaimDueStandingOrder(ctx context.Context) (StandingOrder, error)
+	CompleteTransferBatch(ctx context.Context, arg CompleteTransferBatchParams) (TransferBatch, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	// Expired keys are taken over; a live key yields no row.
+	CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) (IdempotencyKey, error)
+	// A multi-leg transaction has no destination; its credits are recorded as legs.
+	CreateMultiLegTransaction(ctx context.Context, arg CreateMultiLegTransactionParams) (Transaction, error)
+	CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (StandingOrder, error)
+	// transaction_id is only set on execution and failure_reason otherwise.
+	CreateStandingOrderExecution(ctx context.Context, arg CreateStandingOrderExecutionParams) (StandingOrderExecution, error)
+	// original_transaction_id and reason are only set on reversals,
+	// destination_amount and exchange_rate only on conversions.
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error)
+	CreateTransactionLeg(ctx context.Context, arg CreateTransactionLegParams) (TransactionLeg, error)
+	CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error)
+	// transaction_id is only set when the transfer succeeded and error when it failed.
+	CreateTransferBatchItem(ctx context.Context, arg CreateTransferBatchItemParams) (TransferBatchItem, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// Deletes at most $1 expired keys, oldest first.
+	DeleteExpiredIdempotencyKeys(ctx context.Context, limit int32) (int64, error)
 	GetAccount(ctx context.Context, accountID int64) (Account, error)
+	// Locks the row until the end of the transaction without blocking inserts that reference it.
+	GetAccountForUpdate(ctx context.Context, accountID int64) (Account, error)
+	// Locks the rows in account_id order, so transactions locking overlapping sets of accounts can't deadlock.
+	GetAccountsForUpdate(ctx context.Context, accountIds []int64) ([]Account, error)
+	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (IdempotencyKey, error)
+	GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error)
+	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetStandingOrder(ctx context.Context, id int64) (StandingOrder, error)
+	GetStandingOrderForUpdate(ctx context.Context, id int64) (StandingOrder, error)
+	GetTransaction(ctx context.Context, id int64) (Transaction, error)
+	GetTransactionForUpdate(ctx context.Context, id int64) (Transaction, error)
+	GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error)
+	GetUser(ctx context.Context, username string) (User, error)
+	// Serializes changes to what the user owns without blocking inserts that reference the user.
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	HasAccountInCurrency(ctx context.Context, arg HasAccountInCurrencyParams) (bool, error)
+	// Keyset pagination over (created_at, id), newest first. Each direction is matched
+	// separately so the planner can combine idx_source_account and idx_destination_account;
+	// multi-leg transactions are incoming for the accounts they credit. account_amount is
+	// what the transaction moved for the account, its credit on a multi-leg transaction it
+	// didn't pay for, and is the amount min_amount and max_amount filter on.
+	ListAccountTransactions(ctx context.Context, arg ListAccountTransactionsParams) ([]ListAccountTransactionsRow, error)
+	// Keyset pagination over account_id; pass 0 as after_account_id for the first page.
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	ListAccountsByID(ctx context.Context, accountIds []int64) ([]Account, error)
+	// Accounts whose balance differs from the sum of their entries; empty when the ledger is consistent.
+	ListBalanceMismatches(ctx context.Context) ([]ListBalanceMismatchesRow, error)
+	// Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// Active holds past their deadline, oldest first.
+	ListExpiredHolds(ctx context.Context, limit int32) ([]int64, error)
+	// Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+	ListStandingOrderExecutions(ctx context.Context, arg ListStandingOrderExecutionsParams) ([]StandingOrderExecution, error)
+	ListTransactionLegs(ctx context.Context, transactionID int64) ([]TransactionLeg, error)
+	ListTransactionLegsByTransactions(ctx context.Context, transactionIds []int64) ([]TransactionLeg, error)
+	ListTransferBatchItems(ctx context.Context, batchID int64) ([]TransferBatchItem, error)
+	// Moves an active hold to its final status; captured_amount and transaction_id are only set on capture.
+	ResolveHold(ctx context.Context, arg ResolveHoldParams) (Hold, error)
+	// Moves a pending transfer to its final status; transaction_id is only set on execution
+	// and failure_reason on failure.
+	ResolveScheduledTransfer(ctx context.Context, arg ResolveScheduledTransferParams) (ScheduledTransfer, error)
 	UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (Account, error)
+	// Records the progress of an order; ended_at is set once it is no longer active.
+	UpdateStandingOrderRun(ctx context.Context, arg UpdateStandingOrderRunParams) (StandingOrder, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,172 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: scheduled_transfer.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimDueScheduledTransfer = `-- name: ClaimDueScheduledTransfer :one
+SELECT id, from_account_id, to_account_id, amount, execute_at, status, transaction_id, failure_reason, created_at, resolved_at FROM scheduled_transfers
+WHERE status = 'pending'
+  AND execute_at <= now()
+ORDER BY execute_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// Locks the oldest pending transfer that is due, skipping those another worker is executing.
+func (q *Queries) ClaimDueScheduledTransfer(ctx context.Context) (ScheduledTransfer, error) {
+	row := q.db.QueryRow(ctx, claimDueScheduledTransfer)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.ExecuteAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const createScheduledTransfer = `-- name: CreateScheduledTransfer :one
+INSERT INTO scheduled_transfers (
+  from_account_id,
+  to_account_id,
+  amount,
+  execute_at
+)
+VALUES ($1, $2, $3, $4)
+RETURNING id, from_account_id, to_account_id, amount, execute_at, status, transaction_id, failure_reason, created_at, resolved_at
+`
+
+type CreateScheduledTransferParams struct {
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        int64              `json:"amount"`
+	ExecuteAt     pgtype.Timestamptz `json:"execute_at"`
+}
+
+func (q *Queries) CreateScheduledTransfer(ctx context.Context, arg CreateScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRow(ctx, createScheduledTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.ExecuteAt,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.ExecuteAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransfer = `-- name: GetScheduledTransfer :one
+SELECT id, from_account_id, to_account_id, amount, execute_at, status, transaction_id, failure_reason, created_at, resolved_at FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetScheduledTransfer(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRow(ctx, getScheduledTransfer, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.ExecuteAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const getScheduledTransferForUpdate = `-- name: GetScheduledTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, execute_at, status, transaction_id, failure_reason, created_at, resolved_at FROM scheduled_transfers
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetScheduledTransferForUpdate(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	row := q.db.QueryRow(ctx, getScheduledTransferForUpdate, id)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.ExecuteAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
+
+const resolveScheduledTransfer = `-- name: ResolveScheduledTransfer :one
+UPDATE scheduled_transfers
+SET status = $1,
+    transaction_id = $2,
+    failure_reason = $3,
+    resolved_at = now()
+WHERE id = $4
+RETURNING id, from_account_id, to_account_id, amount, execute_at, status, transaction_id, failure_reason, created_at, resolved_at
+`
+
+type ResolveScheduledTransferParams struct {
+	Status        string      `json:"status"`
+	TransactionID pgtype.Int8 `json:"transaction_id"`
+	FailureReason pgtype.Text `json:"failure_reason"`
+	ID            int64       `json:"id"`
+}
+
+// Moves a pending transfer to its final status; transaction_id is only set on execution
+// and failure_reason on failure.
+func (q *Queries) ResolveScheduledTransfer(ctx context.Context, arg ResolveScheduledTransferParams) (ScheduledTransfer, error) {
+	row := q.db.QueryRow(ctx, resolveScheduledTransfer,
+		arg.Status,
+		arg.TransactionID,
+		arg.FailureReason,
+		arg.ID,
+	)
+	var i ScheduledTransfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.ExecuteAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+		&i.ResolvedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,323 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: standing_order.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimDueStandingOrder = `-- name: ClaimDueStandingOrder :one
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, end_at, max_occurrences, insufficient_funds_policy, status, next_run_at, retry_at, retries, occurrences, created_at, ended_at FROM standing_orders
+WHERE status = 'active'
+  AND COALESCE(retry_at, next_run_at) <= now()
+ORDER BY COALESCE(retry_at, next_run_at)
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// Locks the active order whose occurrence or retry is most overdue, skipping those another worker is running.
+func (q *Queries) ClaimDueStandingOrder(ctx context.Context) (StandingOrder, error) {
+	row := q.db.QueryRow(ctx, claimDueStandingOrder)
+	var i StandingOrder
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.EndAt,
+		&i.MaxOccurrences,
+		&i.InsufficientFundsPolicy,
+		&i.Status,
+		&i.NextRunAt,
+		&i.RetryAt,
+		&i.Retries,
+		&i.Occurrences,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const createStandingOrder = `-- name: CreateStandingOrder :one
+INSERT INTO standing_orders (
+  from_account_id,
+  to_account_id,
+  amount,
+  schedule,
+  start_at,
+  end_at,
+  max_occurrences,
+  insufficient_funds_policy,
+  next_run_at
+)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
+)
+RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, end_at, max_occurrences, insufficient_funds_policy, status, next_run_at, retry_at, retries, occurrences, created_at, ended_at
+`
+
+type CreateStandingOrderParams struct {
+	FromAccountID           int64              `json:"from_account_id"`
+	ToAccountID             int64              `json:"to_account_id"`
+	Amount                  int64              `json:"amount"`
+	Schedule                string             `json:"schedule"`
+	StartAt                 pgtype.Timestamptz `json:"start_at"`
+	EndAt                   pgtype.Timestamptz `json:"end_at"`
+	MaxOccurrences          pgtype.Int4        `json:"max_occurrences"`
+	InsufficientFundsPolicy string             `json:"insufficient_funds_policy"`
+	NextRunAt               pgtype.Timestamptz `json:"next_run_at"`
+}
+
+func (q *Queries) CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (StandingOrder, error) {
+	row := q.db.QueryRow(ctx, createStandingOrder,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Schedule,
+		arg.StartAt,
+		arg.EndAt,
+		arg.MaxOccurrences,
+		arg.InsufficientFundsPolicy,
+		arg.NextRunAt,
+	)
+	var i StandingOrder
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.EndAt,
+		&i.MaxOccurrences,
+		&i.InsufficientFundsPolicy,
+		&i.Status,
+		&i.NextRunAt,
+		&i.RetryAt,
+		&i.Retries,
+		&i.Occurrences,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const createStandingOrderExecution = `-- name: CreateStandingOrderExecution :one
+INSERT INTO standing_order_executions (
+  standing_order_id,
+  occurrence_at,
+  status,
+  transaction_id,
+  failure_reason
+)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5
+)
+RETURNING id, standing_order_id, occurrence_at, status, transaction_id, failure_reason, created_at
+`
+
+type CreateStandingOrderExecutionParams struct {
+	StandingOrderID int64              `json:"standing_order_id"`
+	OccurrenceAt    pgtype.Timestamptz `json:"occurrence_at"`
+	Status          string             `json:"status"`
+	TransactionID   pgtype.Int8        `json:"transaction_id"`
+	FailureReason   pgtype.Text        `json:"failure_reason"`
+}
+
+// transaction_id is only set on execution and failure_reason otherwise.
+func (q *Queries) CreateStandingOrderExecution(ctx context.Context, arg CreateStandingOrderExecutionParams) (StandingOrderExecution, error) {
+	row := q.db.QueryRow(ctx, createStandingOrderExecution,
+		arg.StandingOrderID,
+		arg.OccurrenceAt,
+		arg.Status,
+		arg.TransactionID,
+		arg.FailureReason,
+	)
+	var i StandingOrderExecution
+	err := row.Scan(
+		&i.ID,
+		&i.StandingOrderID,
+		&i.OccurrenceAt,
+		&i.Status,
+		&i.TransactionID,
+		&i.FailureReason,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getStandingOrder = `-- name: GetStandingOrder :one
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, end_at, max_occurrences, insufficient_funds_policy, status, next_run_at, retry_at, retries, occurrences, created_at, ended_at FROM standing_orders
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetStandingOrder(ctx context.Context, id int64) (StandingOrder, error) {
+	row := q.db.QueryRow(ctx, getStandingOrder, id)
+	var i StandingOrder
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.EndAt,
+		&i.MaxOccurrences,
+		&i.InsufficientFundsPolicy,
+		&i.Status,
+		&i.NextRunAt,
+		&i.RetryAt,
+		&i.Retries,
+		&i.Occurrences,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getStandingOrderForUpdate = `-- name: GetStandingOrderForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, schedule, start_at, end_at, max_occurrences, insufficient_funds_policy, status, next_run_at, retry_at, retries, occurrences, created_at, ended_at FROM standing_orders
+WHERE id = $1
+LIMIT 1
+FOR UPDATE
+`
+
+func (q *Queries) GetStandingOrderForUpdate(ctx context.Context, id int64) (StandingOrder, error) {
+	row := q.db.QueryRow(ctx, getStandingOrderForUpdate, id)
+	var i StandingOrder
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.EndAt,
+		&i.MaxOccurrences,
+		&i.InsufficientFundsPolicy,
+		&i.Status,
+		&i.NextRunAt,
+		&i.RetryAt,
+		&i.Retries,
+		&i.Occurrences,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const listStandingOrderExecutions = `-- name: ListStandingOrderExecutions :many
+SELECT id, standing_order_id, occurrence_at, status, transaction_id, failure_reason, created_at FROM standing_order_executions
+WHERE standing_order_id = $1
+  AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListStandingOrderExecutionsParams struct {
+	StandingOrderID int64 `json:"standing_order_id"`
+	AfterID         int64 `json:"after_id"`
+	PageLimit       int32 `json:"page_limit"`
+}
+
+// Keyset pagination over id, oldest first; pass 0 as after_id for the first page.
+func (q *Queries) ListStandingOrderExecutions(ctx context.Context, arg ListStandingOrderExecutionsParams) ([]StandingOrderExecution, error) {
+	rows, err := q.db.Query(ctx, listStandingOrderExecutions, arg.StandingOrderID, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StandingOrderExecution
+	for rows.Next() {
+		var i StandingOrderExecution
+		if err := rows.Scan(
+			&i.ID,
+			&i.StandingOrderID,
+			&i.OccurrenceAt,
+			&i.Status,
+			&i.TransactionID,
+			&i.FailureReason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateStandingOrderRun = `-- name: UpdateStandingOrderRun :one
+UPDATE standing_orders
+SET status = $1,
+    next_run_at = $2,
+    retry_at = $3,
+    retries = $4,
+    occurrences = $5,
+    ended_at = CASE WHEN $1::varchar = 'active' THEN NULL ELSE now() END
+WHERE id = $6
+RETURNING id, from_account_id, to_account_id, amount, schedule, start_at, end_at, max_occurrences, insufficient_funds_policy, status, next_run_at, retry_at, retries, occurrences, created_at, ended_at
+`
+
+type UpdateStandingOrderRunParams struct {
+	Status      string             `json:"status"`
+	NextRunAt   pgtype.Timestamptz `json:"next_run_at"`
+	RetryAt     pgtype.Timestamptz `json:"retry_at"`
+	Retries     int32              `json:"retries"`
+	Occurrences int32              `json:"occurrences"`
+	ID          int64              `json:"id"`
+}
+
+// Records the progress of an order; ended_at is set once it is no longer active.
+func (q *Queries) UpdateStandingOrderRun(ctx context.Context, arg UpdateStandingOrderRunParams) (StandingOrder, error) {
+	row := q.db.QueryRow(ctx, updateStandingOrderRun,
+		arg.Status,
+		arg.NextRunAt,
+		arg.RetryAt,
+		arg.Retries,
+		arg.Occurrences,
+		arg.ID,
+	)
+	var i StandingOrder
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Schedule,
+		&i.StartAt,
+		&i.EndAt,
+		&i.MaxOccurrences,
+		&i.InsufficientFundsPolicy,
+		&i.Status,
+		&i.NextRunAt,
+		&i.RetryAt,
+		&i.Retries,
+		&i.Occurrences,
+		&i.CreatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
@@ -11,36 +11,52 @@ import (
 type Store interface {
 	Querier
 
+	CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	TransferBatchTx(ctx context.Context, arg TransferBatchTxParams) (TransferBatchTxResult, error)
+	MultiLegTransferTx(ctx context.Context, arg MultiLegTransferTxParams) (MultiLegTransferTxResult, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (ReverseTransferTxResult, error)
+	CreateHoldTx(ctx context.Context, arg CreateHoldTxParams) (HoldTxResult, error)
+	CaptureHoldTx(ctx context.Context, arg CaptureHoldTxParams) (CaptureHoldTxResult, error)
+	VoidHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error)
+	ExpireHoldsTx(ctx context.Context, limit int32) (int, error)
+	CancelScheduledTransferTx(ctx context.Context, id int64) (ScheduledTransfer, error)
+	ExecuteScheduledTransfersTx(ctx context.Context, limit int32) (ExecuteScheduledTransfersResult, error)
+	CancelStandingOrderTx(ctx context.Context, id int64) (StandingOrder, error)
+	ExecuteStandingOrdersTx(ctx context.Context, args ExecuteStandingOrdersTxParams) (ExecuteStandingOrdersResult, error)
 }
 
 // SQLStore provides all functions to execute db queries and transactions.
 type SQLStore struct {
 	db *pgxpool.Pool
 	*Queries
+	txConfig TxConfig
 }
 
 // NewStore creates a new SQLStore instance with the given database connection.
-func NewStore(db *pgxpool.Pool) Store {
+// Errors from its queries are translated into the sentinel errors of this package,
+// and transfers run as configured by txConfig.
+func NewStore(db *pgxpool.Pool, txConfig TxConfig) Store {
 	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+		db:       db,
+		Queries:  New(errorTranslatingDBTX{db}),
+		txConfig: txConfig,
 	}
 }
 
 // execTx runs a function within a database transaction.
-func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, pgx.TxOptions{})
+func (store *SQLStore) execTx(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, txOptions)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 	defer tx.Rollback(ctx)
 
-	q := New(tx)
+	q := New(errorTranslatingDBTX{tx})
 
 	if err := fn(q); err != nil {
 		return err
 	}
 
-	return tx.Commit(ctx)
+	return translateError(tx.Commit(ctx))
 }
@@ -0,0 +1,285 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+// requireLedgerConsistent checks that the balances of the accounts equal the sum of their entries.
+// Other tests create accounts with raw queries that bypass the ledger, so only the given accounts are checked.
+func requireLedgerConsistent(t *testing.T, accountIDs ...int64) {
+	mismatches, err := testQueries.ListBalanceMismatches(context.Background())
+	require.NoError(t, err)
+
+	for _, mismatch := range mismatches {
+		require.NotContains(t, accountIDs, mismatch.AccountID)
+	}
+}
+
+func TestCreateAccountTx(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     user.Username,
+			Balance:   util.RandomInt(1, 1000),
+			Currency:  util.RandomCurrency(),
+		},
+	}
+
+	account, err := testStore.CreateAccountTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Balance, account.Balance)
+
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.AccountID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, arg.Balance, entries[0].Amount)
+	require.Equal(t, arg.Balance, entries[0].Balance)
+	require.False(t, entries[0].TransactionID.Valid)
+
+	next, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.AccountID,
+		AfterID:   entries[0].ID,
+		PageLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, next)
+}
+
+func TestCreateAccountTxOnePerCurrency(t *testing.T) {
+	user := createRandomUser(t)
+
+	n := 5
+	errs := make(chan error)
+
+	// concurrent requests for the same currency: the owner lock lets exactly one through
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+				CreateAccountParams: CreateAccountParams{
+					AccountID: util.RandomAccountID(),
+					Owner:     user.Username,
+					Currency:  util.USD,
+				},
+				OnePerCurrency: true,
+			})
+			errs <- err
+		}()
+	}
+
+	created := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			created++
+			continue
+		}
+		require.ErrorIs(t, err, ErrUniqueViolation)
+	}
+	require.Equal(t, 1, created)
+
+	// another currency is still allowed
+	_, err := testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     user.Username,
+			Currency:  util.EUR,
+		},
+		OnePerCurrency: true,
+	})
+	require.NoError(t, err)
+}
+
+func TestTransferTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 1000)
+	account2 := createRandomLedgerAccount(t, 1000)
+
+	n := 5
+	amount := int64(10)
+
+	errs := make(chan error)
+	results := make(chan TransferTxResult)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.AccountID,
+				ToAccountID:   account2.AccountID,
+				Amount:        amount,
+			})
+
+			errs <- err
+			results <- result
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+
+		result := <-results
+		require.NotZero(t, result.Transaction.ID)
+
+		fromEntry := result.FromEntry
+		require.Equal(t, account1.AccountID, fromEntry.AccountID)
+		require.Equal(t, -amount, fromEntry.Amount)
+		require.Equal(t, result.Transaction.ID, fromEntry.TransactionID.Int64)
+		require.Equal(t, result.FromAccount.Balance, fromEntry.Balance)
+
+		toEntry := result.ToEntry
+		require.Equal(t, account2.AccountID, toEntry.AccountID)
+		require.Equal(t, amount, toEntry.Amount)
+		require.Equal(t, result.Transaction.ID, toEntry.TransactionID.Int64)
+		require.Equal(t, result.ToAccount.Balance, toEntry.Balance)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-int64(n)*amount, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance+int64(n)*amount, updatedAccount2.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestTransferTxDeadlock(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 1000)
+	account2 := createRandomLedgerAccount(t, 1000)
+
+	n := 10
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccountID, toAccountID := account1.AccountID, account2.AccountID
+		if i%2 == 1 {
+			fromAccountID, toAccountID = toAccountID, fromAccountID
+		}
+
+		go func() {
+			_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestTransferTxInsufficientFunds(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 100)
+
+	_, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.AccountID,
+		ToAccountID:   account2.AccountID,
+		Amount:        101,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, account1.AccountID, fundsErr.AccountID)
+	require.Equal(t, int64(100), fundsErr.Available)
+	require.Equal(t, int64(101), fundsErr.Requested)
+
+	transactions, err := testQueries.ListAccountTransactions(context.Background(), ListAccountTransactionsParams{
+		AccountID:       account1.AccountID,
+		IncludeOutgoing: true,
+		IncludeIncoming: true,
+		PageLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transactions)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestTransferTxIdempotency(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 100)
+
+	idempotency := &IdempotencyParams{
+		Username:    account1.Owner,
+		Key:         util.RandomString(16),
+		RequestHash: util.RandomString(64),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		RenderResponse: func(result TransferTxResult) ([]byte, error) {
+			return []byte(fmt.Sprintf("transaction %d", result.Transaction.ID)), nil
+		},
+	}
+	arg := TransferTxParams{
+		FromAccountID: account1.AccountID,
+		ToAccountID:   account2.AccountID,
+		Amount:        10,
+		Idempotency:   idempotency,
+	}
+
+	result, err := testStore.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	// the rendered response is stored as is, in the transaction of the transfer
+	key, err := testQueries.GetIdempotencyKey(context.Background(), GetIdempotencyKeyParams{
+		Username: idempotency.Username,
+		Key:      idempotency.Key,
+	})
+	require.NoError(t, err)
+	require.Equal(t, idempotency.RequestHash, key.RequestHash)
+	require.Equal(t, fmt.Sprintf("transaction %d", result.Transaction.ID), string(key.ResponseBody))
+
+	// a second transfer with the live key is rolled back
+	_, err = testStore.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrIdempotencyKeyInUse)
+
+	requireBalance(t, account1.AccountID, 90)
+	requireBalance(t, account2.AccountID, 110)
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+// createRandomLedgerAccount creates an account through the store so its opening balance is recorded as an entry.
+func createRandomLedgerAccount(t *testing.T, balance int64) Account {
+	user := createRandomUser(t)
+
+	account, err := testStore.CreateAccountTx(context.Background(), CreateAccountTxParams{
+		CreateAccountParams: CreateAccountParams{
+			AccountID: util.RandomAccountID(),
+			Owner:     user.Username,
+			Balance:   balance,
+			Currency:  util.USD,
+		},
+	})
+	require.NoError(t, err)
+
+	return account
+}
@@ -7,26 +7,117 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addReversedAmount = `-- name: AddReversedAmount :one
+UPDATE transactions
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, source_account_id, destination_account_id, amount, created_at, original_transaction_id, reason, reversed_amount, destination_amount, exchange_rate
+`
+
+type AddReversedAmountParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) AddReversedAmount(ctx context.Context, arg AddReversedAmountParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, addReversedAmount, arg.Amount, arg.ID)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.SourceAccountID,
+		&i.DestinationAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.OriginalTransactionID,
+		&i.Reason,
+		&i.ReversedAmount,
+		&i.DestinationAmount,
+		&i.ExchangeRate,
+	)
+	return i, err
+}
+
+const createMultiLegTransaction = `-- name: CreateMultiLegTransaction :one
+INSERT INTO transactions (
+  source_account_id,
+  amount
 )
+VALUES ($1, $2)
+RETURNING id, source_account_id, destination_account_id, amount, created_at, original_transaction_id, reason, reversed_amount, destination_amount, exchange_rate
+`
+
+type CreateMultiLegTransactionParams struct {
+	SourceAccountID int64 `json:"source_account_id"`
+	Amount          int64 `json:"amount"`
+}
+
+// A multi-leg transaction has no destination; its credits are recorded as legs.
+func (q *Queries) CreateMultiLegTransaction(ctx context.Context, arg CreateMultiLegTransactionParams) (Transaction, error) {
+	row := q.db.QueryRow(ctx, createMultiLegTransaction, arg.SourceAccountID, arg.Amount)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.SourceAccountID,
+		&i.DestinationAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.OriginalTransactionID,
+		&i.Reason,
+		&i.ReversedAmount,
+		&i.DestinationAmount,
+		&i.ExchangeRate,
+	)
+	return i, err
+}
 
 const createTransaction = `-- name: CreateTransaction :one
 INSERT INTO transactions (
   source_account_id,
   destination_account_id,
-  amount
+  amount,
+  original_transaction_id,
+  reason,
+  destination_amount,
+  exchange_rate
 )
-VALUES ($1, $2, $3)
-RETURNING id, source_account_id, destination_account_id, amount, created_at
+VALUES (
+  $1,
+  $2::bigint,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7
+)
+RETURNING id, source_account_id, destination_account_id, amount, created_at, original_transaction_id, reason, reversed_amount, destination_amount, exchange_rate
 `
 
 type CreateTransactionParams struct {
-	SourceAccountID      int64 `json:"source_account_id"`
-	DestinationAccountID int64 `json:"destination_account_id"`
-	Amount               int64 `json:"amount"`
+	SourceAccountID       int64          `json:"source_account_id"`
+	DestinationAccountID  int64          `json:"destination_account_id"`
+	Amount                int64          `json:"amount"`
+	OriginalTransactionID pgtype.Int8    `json:"original_transaction_id"`
+	Reason                pgtype.Text    `json:"reason"`
+	DestinationAmount     pgtype.Int8    `json:"destination_amount"`
+	ExchangeRate          pgtype.Numeric `json:"exchange_rate"`
 }
 
+// original_transaction_id and reason are only set on reversals,
+// destination_amount and exchange_rate only on conversions.
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
-	row := q.db.QueryRow(ctx, createTransaction, arg.SourceAccountID, arg.DestinationAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransaction,
+		arg.SourceAccountID,
+		arg.DestinationAccountID,
+		arg.Amount,
+		arg.OriginalTransactionID,
+		arg.Reason,
+		arg.DestinationAmount,
+		arg.ExchangeRate,
+	)
 	var i Transaction
 	err := row.Scan(
 		&i.ID,
@@ -34,6 +125,264 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.DestinationAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.OriginalTransactionID,
+		&i.Reason,
+		&i.ReversedAmount,
+		&i.DestinationAmount,
+		&i.ExchangeRate,
 	)
 	return i, err
 }
+
+const createTransactionLeg = `-- name: CreateTransactionLeg :one
+INSERT INTO transaction_legs (
+  transaction_id,
+  position,
+  account_id,
+  amount,
+  memo
+)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING transaction_id, position, account_id, amount, memo
+`
+
+type CreateTransactionLegParams struct {
+	TransactionID int64  `json:"transaction_id"`
+	Position      int32  `json:"position"`
+	AccountID     int64  `json:"account_id"`
+	Amount        int64  `json:"amount"`
+	Memo          string `json:"memo"`
+}
+
+func (q *Queries) CreateTransactionLeg(ctx context.Context, arg CreateTransactionLegParams) (TransactionLeg, error) {
+	row := q.db.QueryRow(ctx, createTransactionLeg,
+		arg.TransactionID,
+		arg.Position,
+		arg.AccountID,
+		arg.Amount,
+		arg.Memo,
+	)
+	var i TransactionLeg
+	err := row.Scan(
+		&i.TransactionID,
+		&i.Position,
+		&i.AccountID,
+		&i.Amount,
+		&i.Memo,
+	)
+	return i, err
+}
+
+const getTransaction = `-- name: GetTransaction :one
+SELECT id, source_account_id, destination_account_id, amount, created_at, original_transaction_id, reason, reversed_amount, destination_amount, exchange_rate FROM transactions
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTransaction(ctx context.Context, id int64) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransaction, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.SourceAccountID,
+		&i.DestinationAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.OriginalTransactionID,
+		&i.Reason,
+		&i.ReversedAmount,
+		&i.DestinationAmount,
+		&i.ExchangeRate,
+	)
+	return i, err
+}
+
+const getTransactionForUpdate = `-- name: GetTransactionForUpdate :one
+SELECT id, source_account_id, destination_account_id, amount, created_at, original_transaction_id, reason, reversed_amount, destination_amount, exchange_rate FROM transactions
+WHERE id = $1
+LIMIT 1
+FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransactionForUpdate(ctx context.Context, id int64) (Transaction, error) {
+	row := q.db.QueryRow(ctx, getTransactionForUpdate, id)
+	var i Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.SourceAccountID,
+		&i.DestinationAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.OriginalTransactionID,
+		&i.Reason,
+		&i.ReversedAmount,
+		&i.DestinationAmount,
+		&i.ExchangeRate,
+	)
+	return i, err
+}
+
+const listAccountTransactions = `-- name: ListAccountTransactions :many
+SELECT transactions.id, transactions.source_account_id, transactions.destination_account_id, transactions.amount, transactions.created_at, transactions.original_transaction_id, transactions.reason, transactions.reversed_amount, transactions.destination_amount, transactions.exchange_rate, movement.account_amount
+FROM transactions
+CROSS JOIN LATERAL (
+  SELECT CASE
+    WHEN destination_account_id IS NULL AND source_account_id <> $1 THEN (
+      SELECT COALESCE(SUM(l.amount), 0) FROM transaction_legs l
+      WHERE l.transaction_id = transactions.id AND l.account_id = $1 AND l.amount > 0
+    )
+    ELSE amount
+  END::bigint AS account_amount
+) movement
+WHERE (
+    (source_account_id = $1 AND $2::boolean)
+    OR (destination_account_id = $1 AND $3::boolean)
+    OR (
+      destination_account_id IS NULL AND $3::boolean
+      AND EXISTS (
+        SELECT 1 FROM transaction_legs l
+        WHERE l.transaction_id = transactions.id AND l.account_id = $1 AND l.amount > 0
+      )
+    )
+  )
+  AND ($4::bigint IS NULL OR movement.account_amount >= $4)
+  AND ($5::bigint IS NULL OR movement.account_amount <= $5)
+  AND ($6::timestamptz IS NULL OR created_at >= $6)
+  AND ($7::timestamptz IS NULL OR created_at < $7)
+  AND (
+    $8::timestamptz IS NULL
+    OR (created_at, id) < ($8, $9::bigint)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT $10
+`
+
+type ListAccountTransactionsParams struct {
+	AccountID       int64              `json:"account_id"`
+	IncludeOutgoing bool               `json:"include_outgoing"`
+	IncludeIncoming bool               `json:"include_incoming"`
+	MinAmount       pgtype.Int8        `json:"min_amount"`
+	MaxAmount       pgtype.Int8        `json:"max_amount"`
+	CreatedFrom     pgtype.Timestamptz `json:"created_from"`
+	CreatedTo       pgtype.Timestamptz `json:"created_to"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	PageLimit       int32              `json:"page_limit"`
+}
+
+type ListAccountTransactionsRow struct {
+	Transaction   Transaction `json:"transaction"`
+	AccountAmount int64       `json:"account_amount"`
+}
+
+// Keyset pagination over (created_at, id), newest first. Each direction is matched
+// separately so the planner can combine idx_source_account and idx_destination_account;
+// multi-leg transactions are incoming for the accounts they credit. account_amount is
+// what the transaction moved for the account, its credit on a multi-leg transaction it
+// didn't pay for, and is the amount min_amount and max_amount filter on.
+func (q *Queries) ListAccountTransactions(ctx context.Context, arg ListAccountTransactionsParams) ([]ListAccountTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, listAccountTransactions,
+		arg.AccountID,
+		arg.IncludeOutgoing,
+		arg.IncludeIncoming,
+		arg.MinAmount,
+		arg.MaxAmount,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAccountTransactionsRow
+	for rows.Next() {
+		var i ListAccountTransactionsRow
+		if err := rows.Scan(
+			&i.Transaction.ID,
+			&i.Transaction.SourceAccountID,
+			&i.Transaction.DestinationAccountID,
+			&i.Transaction.Amount,
+			&i.Transaction.CreatedAt,
+			&i.Transaction.OriginalTransactionID,
+			&i.Transaction.Reason,
+			&i.Transaction.ReversedAmount,
+			&i.Transaction.DestinationAmount,
+			&i.Transaction.ExchangeRate,
+			&i.AccountAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionLegs = `-- name: ListTransactionLegs :many
+SELECT transaction_id, position, account_id, amount, memo FROM transaction_legs
+WHERE transaction_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListTransactionLegs(ctx context.Context, transactionID int64) ([]TransactionLeg, error) {
+	rows, err := q.db.Query(ctx, listTransactionLegs, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TransactionLeg
+	for rows.Next() {
+		var i TransactionLeg
+		if err := rows.Scan(
+			&i.TransactionID,
+			&i.Position,
+			&i.AccountID,
+			&i.Amount,
+			&i.Memo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTransactionLegsByTransactions = `-- name: ListTransactionLegsByTransactions :many
+SELECT transaction_id, position, account_id, amount, memo FROM transaction_legs
+WHERE transaction_id = ANY($1::bigint[])
+ORDER BY transaction_id, position
+`
+
+func (q *Queries) ListTransactionLegsByTransactions(ctx context.Context, transactionIds []int64) ([]TransactionLeg, error) {
+	rows, err := q.db.Query(ctx, listTransactionLegsByTransactions, transactionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TransactionLeg
+	for rows.Next() {
+		var i TransactionLeg
+		if err := rows.Scan(
+			&i.TransactionID,
+			&i.Position,
+			&i.AccountID,
+			&i.Amount,
+			&i.Memo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
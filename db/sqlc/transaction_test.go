@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -23,7 +24,7 @@ func createRandomTransaction(t *testing.T) Transaction {
 	require.NotEmpty(t, tx)
 
 	require.Equal(t, arg.SourceAccountID, tx.SourceAccountID)
-	require.Equal(t, arg.DestinationAccountID, tx.DestinationAccountID)
+	require.Equal(t, arg.DestinationAccountID, tx.DestinationAccountID.Int64)
 	require.Equal(t, arg.Amount, tx.Amount)
 	require.NotZero(t, tx.ID)
 	require.NotZero(t, tx.CreatedAt)
@@ -81,3 +82,74 @@ func TestCreateTransactionSameAccountFails(t *testing.T) {
 	require.Empty(t, tx)
 	require.Contains(t, err.Error(), "check constraint")
 }
+
+func TestGetTransaction(t *testing.T) {
+	tx1 := createRandomTransaction(t)
+
+	tx2, err := testQueries.GetTransaction(context.Background(), tx1.ID)
+	require.NoError(t, err)
+	require.Equal(t, tx1, tx2)
+}
+
+func TestListAccountTransactions(t *testing.T) {
+	account := createRandomAccount(t)
+	other := createRandomAccount(t)
+
+	for i := 0; i < 3; i++ {
+		_, err := testQueries.CreateTransaction(context.Background(), CreateTransactionParams{
+			SourceAccountID:      account.AccountID,
+			DestinationAccountID: other.AccountID,
+			Amount:               int64(i + 1),
+		})
+		require.NoError(t, err)
+
+		_, err = testQueries.CreateTransaction(context.Background(), CreateTransactionParams{
+			SourceAccountID:      other.AccountID,
+			DestinationAccountID: account.AccountID,
+			Amount:               int64(i + 10),
+		})
+		require.NoError(t, err)
+	}
+
+	arg := ListAccountTransactionsParams{
+		AccountID:       account.AccountID,
+		IncludeOutgoing: true,
+		IncludeIncoming: true,
+		PageLimit:       4,
+	}
+
+	page1, err := testQueries.ListAccountTransactions(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page1, 4)
+
+	last := page1[len(page1)-1]
+	arg.CursorCreatedAt = last.Transaction.CreatedAt
+	arg.CursorID = pgtype.Int8{Int64: last.Transaction.ID, Valid: true}
+
+	page2, err := testQueries.ListAccountTransactions(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, page2, 2)
+
+	all := append(page1, page2...)
+	for i := 1; i < len(all); i++ {
+		prev, cur := all[i-1].Transaction, all[i].Transaction
+		require.True(t, prev.CreatedAt.Time.After(cur.CreatedAt.Time) ||
+			(prev.CreatedAt.Time.Equal(cur.CreatedAt.Time) && prev.ID > cur.ID))
+	}
+
+	arg = ListAccountTransactionsParams{
+		AccountID:       account.AccountID,
+		IncludeIncoming: true,
+		MinAmount:       pgtype.Int8{Int64: 11, Valid: true},
+		PageLimit:       10,
+	}
+
+	filtered, err := testQueries.ListAccountTransactions(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, filtered, 2)
+	for _, row := range filtered {
+		require.Equal(t, account.AccountID, row.Transaction.DestinationAccountID.Int64)
+		require.GreaterOrEqual(t, row.Transaction.Amount, int64(11))
+		require.Equal(t, row.Transaction.Amount, row.AccountAmount)
+	}
+}
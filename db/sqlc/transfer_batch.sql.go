@@ -0,0 +1,183 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: transfer_batch.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const completeTransferBatch = `-- name: CompleteTransferBatch :one
+UPDATE transfer_batches
+SET status = $1,
+    completed_at = now()
+WHERE id = $2
+RETURNING id, owner, mode, status, created_at, completed_at
+`
+
+type CompleteTransferBatchParams struct {
+	Status string `json:"status"`
+	ID     int64  `json:"id"`
+}
+
+func (q *Queries) CompleteTransferBatch(ctx context.Context, arg CompleteTransferBatchParams) (TransferBatch, error) {
+	row := q.db.QueryRow(ctx, completeTransferBatch, arg.Status, arg.ID)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Mode,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createTransferBatch = `-- name: CreateTransferBatch :one
+INSERT INTO transfer_batches (
+  owner,
+  mode
+)
+VALUES ($1, $2)
+RETURNING id, owner, mode, status, created_at, completed_at
+`
+
+type CreateTransferBatchParams struct {
+	Owner string `json:"owner"`
+	Mode  string `json:"mode"`
+}
+
+func (q *Queries) CreateTransferBatch(ctx context.Context, arg CreateTransferBatchParams) (TransferBatch, error) {
+	row := q.db.QueryRow(ctx, createTransferBatch, arg.Owner, arg.Mode)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Mode,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createTransferBatchItem = `-- name: CreateTransferBatchItem :one
+INSERT INTO transfer_batch_items (
+  batch_id,
+  position,
+  from_account_id,
+  to_account_id,
+  amount,
+  status,
+  transaction_id,
+  error
+)
+VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8
+)
+RETURNING batch_id, position, from_account_id, to_account_id, amount, status, transaction_id, error
+`
+
+type CreateTransferBatchItemParams struct {
+	BatchID       int64       `json:"batch_id"`
+	Position      int32       `json:"position"`
+	FromAccountID int64       `json:"from_account_id"`
+	ToAccountID   int64       `json:"to_account_id"`
+	Amount        int64       `json:"amount"`
+	Status        string      `json:"status"`
+	TransactionID pgtype.Int8 `json:"transaction_id"`
+	Error         pgtype.Text `json:"error"`
+}
+
+// transaction_id is only set when the transfer succeeded and error when it failed.
+func (q *Queries) CreateTransferBatchItem(ctx context.Context, arg CreateTransferBatchItemParams) (TransferBatchItem, error) {
+	row := q.db.QueryRow(ctx, createTransferBatchItem,
+		arg.BatchID,
+		arg.Position,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Status,
+		arg.TransactionID,
+		arg.Error,
+	)
+	var i TransferBatchItem
+	err := row.Scan(
+		&i.BatchID,
+		&i.Position,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Status,
+		&i.TransactionID,
+		&i.Error,
+	)
+	return i, err
+}
+
+const getTransferBatch = `-- name: GetTransferBatch :one
+SELECT id, owner, mode, status, created_at, completed_at FROM transfer_batches
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTransferBatch(ctx context.Context, id int64) (TransferBatch, error) {
+	row := q.db.QueryRow(ctx, getTransferBatch, id)
+	var i TransferBatch
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Mode,
+		&i.Status,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const listTransferBatchItems = `-- name: ListTransferBatchItems :many
+SELECT batch_id, position, from_account_id, to_account_id, amount, status, transaction_id, error FROM transfer_batch_items
+WHERE batch_id = $1
+ORDER BY position
+`
+
+func (q *Queries) ListTransferBatchItems(ctx context.Context, batchID int64) ([]TransferBatchItem, error) {
+	rows, err := q.db.Query(ctx, listTransferBatchItems, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TransferBatchItem
+	for rows.Next() {
+		var i TransferBatchItem
+		if err := rows.Scan(
+			&i.BatchID,
+			&i.Position,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Status,
+			&i.TransactionID,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CreateAccountTxParams contains the input parameters for opening an account
+type CreateAccountTxParams struct {
+	CreateAccountParams
+	// OnePerCurrency rejects the account with ErrUniqueViolation if the owner already has one in its currency
+	OnePerCurrency bool `json:"one_per_currency"`
+}
+
+// CreateAccountTx creates an account and records its initial balance as an opening entry.
+// With OnePerCurrency set, the owner's user row is locked first, so concurrent requests
+// can't both open an account in the same currency.
+func (s *SQLStore) CreateAccountTx(ctx context.Context, arg CreateAccountTxParams) (Account, error) {
+	var account Account
+	err := s.execTx(ctx, pgx.TxOptions{}, func(q *Queries) error {
+		var err error
+
+		if arg.OnePerCurrency {
+			if err := checkAccountCurrencyFree(ctx, q, arg.Owner, arg.Currency); err != nil {
+				return err
+			}
+		}
+
+		account, err = q.CreateAccount(ctx, arg.CreateAccountParams)
+		if err != nil {
+			return err
+		}
+
+		if account.Balance == 0 {
+			return nil
+		}
+
+		_, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: account.AccountID,
+			Amount:    account.Balance,
+			Balance:   account.Balance,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create opening entry: %w", err)
+		}
+
+		return nil
+	})
+
+	return account, err
+}
+
+// checkAccountCurrencyFree locks the owner and fails if they already have an account in the currency.
+// An unknown owner is left for the foreign key on accounts to reject.
+func checkAccountCurrencyFree(ctx context.Context, q *Queries, owner, currency string) error {
+	if _, err := q.GetUserForUpdate(ctx, owner); err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to lock owner: %w", err)
+	}
+
+	exists, err := q.HasAccountInCurrency(ctx, HasAccountInCurrencyParams{
+		Owner:    owner,
+		Currency: currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check accounts of owner: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w: owner already has a %s account", ErrUniqueViolation, currency)
+	}
+
+	return nil
+}
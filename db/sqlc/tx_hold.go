@@ -0,0 +1,239 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a hold; only active holds count towards the held balance of their account
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+// CreateHoldTxParams contains the input parameters for placing a hold
+type CreateHoldTxParams struct {
+	AccountID   int64     `json:"account_id"`
+	ToAccountID int64     `json:"to_account_id"`
+	Amount      int64     `json:"amount"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// HoldTxResult contains a hold and its account after placing or releasing the hold
+type HoldTxResult struct {
+	Hold    Hold    `json:"hold"`
+	Account Account `json:"account"`
+}
+
+// CaptureHoldTxParams contains the input parameters for capturing a hold
+type CaptureHoldTxParams struct {
+	HoldID int64 `json:"hold_id"`
+	// Amount to transfer; 0 captures the full held amount
+	Amount int64 `json:"amount"`
+}
+
+// CaptureHoldTxResult contains the captured hold and the transfer it turned into
+type CaptureHoldTxResult struct {
+	Hold     Hold             `json:"hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CreateHoldTx reserves part of the available balance of an account for a later transfer to another account.
+// The ledger balance is unchanged until the hold is captured.
+func (s *SQLStore) CreateHoldTx(ctx context.Context, args CreateHoldTxParams) (HoldTxResult, error) {
+	var result HoldTxResult
+	err := s.execTxWithRetry(ctx, "CreateHoldTx", func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, args.AccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock account: %w", err)
+		}
+
+		if available := account.Balance - account.HeldBalance; available < args.Amount {
+			return &InsufficientFundsError{
+				AccountID: args.AccountID,
+				Available: available,
+				Requested: args.Amount,
+			}
+		}
+
+		result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+			AccountID:   args.AccountID,
+			ToAccountID: args.ToAccountID,
+			Amount:      args.Amount,
+			ExpiresAt:   pgtype.Timestamptz{Time: args.ExpiresAt, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create hold: %w", err)
+		}
+
+		result.Account, err = q.AddHeldBalance(ctx, AddHeldBalanceParams{
+			AccountID: args.AccountID,
+			Amount:    args.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update held balance: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CaptureHoldTx releases an active hold and transfers all or part of its amount to the destination account.
+// Any amount not captured becomes available again.
+func (s *SQLStore) CaptureHoldTx(ctx context.Context, args CaptureHoldTxParams) (CaptureHoldTxResult, error) {
+	var result CaptureHoldTxResult
+	err := s.execTxWithRetry(ctx, "CaptureHoldTx", func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, args.HoldID)
+		if err != nil {
+			return err
+		}
+
+		amount := args.Amount
+		if amount == 0 {
+			amount = hold.Amount
+		}
+		if amount > hold.Amount {
+			return fmt.Errorf("%w: held %d, requested %d", ErrHoldAmountExceeded, hold.Amount, amount)
+		}
+
+		if _, err := lockAccounts(ctx, q, hold.AccountID, hold.ToAccountID); err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+
+		// release the hold first so the transfer can spend the funds it reserved
+		fromAccount, err := q.AddHeldBalance(ctx, AddHeldBalanceParams{
+			AccountID: hold.AccountID,
+			Amount:    -hold.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update held balance: %w", err)
+		}
+
+		result.Transfer, err = transfer(ctx, q, fromAccount, CreateTransactionParams{
+			SourceAccountID:      hold.AccountID,
+			DestinationAccountID: hold.ToAccountID,
+			Amount:               amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Hold, err = q.ResolveHold(ctx, ResolveHoldParams{
+			ID:             hold.ID,
+			Status:         HoldStatusCaptured,
+			CapturedAmount: pgtype.Int8{Int64: amount, Valid: true},
+			TransactionID:  pgtype.Int8{Int64: result.Transfer.Transaction.ID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve hold: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// VoidHoldTx cancels an active hold, making its amount available again.
+func (s *SQLStore) VoidHoldTx(ctx context.Context, holdID int64) (HoldTxResult, error) {
+	var result HoldTxResult
+	err := s.execTxWithRetry(ctx, "VoidHoldTx", func(q *Queries) error {
+		hold, err := lockActiveHold(ctx, q, holdID)
+		if err != nil {
+			return err
+		}
+
+		result, err = releaseHold(ctx, q, hold, HoldStatusVoided)
+		return err
+	})
+
+	return result, err
+}
+
+// ExpireHoldsTx releases up to limit active holds past their deadline, each in its own transaction,
+// and returns how many it expired. Holds captured or voided concurrently are skipped.
+func (s *SQLStore) ExpireHoldsTx(ctx context.Context, limit int32) (int, error) {
+	holdIDs, err := s.ListExpiredHolds(ctx, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired holds: %w", err)
+	}
+
+	expired := 0
+	for _, holdID := range holdIDs {
+		err := s.execTxWithRetry(ctx, "ExpireHoldsTx", func(q *Queries) error {
+			hold, err := q.GetHoldForUpdate(ctx, holdID)
+			if err != nil {
+				return err
+			}
+
+			if hold.Status != HoldStatusActive || hold.ExpiresAt.Time.After(time.Now()) {
+				return errHoldSkipped
+			}
+
+			_, err = releaseHold(ctx, q, hold, HoldStatusExpired)
+			return err
+		})
+		if errors.Is(err, errHoldSkipped) {
+			continue
+		}
+		if err != nil {
+			return expired, fmt.Errorf("failed to expire hold %d: %w", holdID, err)
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// errHoldSkipped rolls back the expiry of a hold that was resolved after it was listed
+var errHoldSkipped = errors.New("hold skipped")
+
+// lockActiveHold locks a hold for the rest of the transaction, failing unless it can still be captured or voided
+func lockActiveHold(ctx context.Context, q *Queries, holdID int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, holdID)
+	if err != nil {
+		return hold, fmt.Errorf("failed to lock hold: %w", err)
+	}
+
+	if hold.Status != HoldStatusActive {
+		return hold, fmt.Errorf("%w: hold %d is %s", ErrHoldNotActive, holdID, hold.Status)
+	}
+
+	if !hold.ExpiresAt.Time.After(time.Now()) {
+		return hold, fmt.Errorf("%w: hold %d has expired", ErrHoldNotActive, holdID)
+	}
+
+	return hold, nil
+}
+
+// releaseHold gives the amount of a locked active hold back to its account and moves the hold to status
+func releaseHold(ctx context.Context, q *Queries, hold Hold, status string) (HoldTxResult, error) {
+	var result HoldTxResult
+	var err error
+
+	result.Account, err = q.AddHeldBalance(ctx, AddHeldBalanceParams{
+		AccountID: hold.AccountID,
+		Amount:    -hold.Amount,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to update held balance: %w", err)
+	}
+
+	result.Hold, err = q.ResolveHold(ctx, ResolveHoldParams{
+		ID:     hold.ID,
+		Status: status,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to resolve hold: %w", err)
+	}
+
+	return result, nil
+}
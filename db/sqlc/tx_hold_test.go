@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomHold(t *testing.T, account, toAccount Account, amount int64) Hold {
+	result, err := testStore.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		AccountID:   account.AccountID,
+		ToAccountID: toAccount.AccountID,
+		Amount:      amount,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, result.Hold.ID)
+	require.Equal(t, HoldStatusActive, result.Hold.Status)
+	require.Equal(t, amount, result.Hold.Amount)
+	require.False(t, result.Hold.ResolvedAt.Valid)
+
+	return result.Hold
+}
+
+func TestCreateHoldTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	createRandomHold(t, account1, account2, 60)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+	require.Equal(t, int64(60), account.HeldBalance)
+
+	// the held amount can be neither held again nor transferred
+	_, err = testStore.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		AccountID:   account1.AccountID,
+		ToAccountID: account2.AccountID,
+		Amount:      41,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, int64(40), fundsErr.Available)
+
+	_, err = testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.AccountID,
+		ToAccountID:   account2.AccountID,
+		Amount:        41,
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestCaptureHoldTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	hold := createRandomHold(t, account1, account2, 60)
+
+	_, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 61})
+	require.ErrorIs(t, err, ErrHoldAmountExceeded)
+
+	result, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID, Amount: 45})
+	require.NoError(t, err)
+
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, int64(45), result.Hold.CapturedAmount.Int64)
+	require.Equal(t, result.Transfer.Transaction.ID, result.Hold.TransactionID.Int64)
+	require.True(t, result.Hold.ResolvedAt.Valid)
+
+	// the part of the hold that wasn't captured is available again
+	require.Equal(t, int64(55), result.Transfer.FromAccount.Balance)
+	require.Zero(t, result.Transfer.FromAccount.HeldBalance)
+	require.Equal(t, int64(45), result.Transfer.ToAccount.Balance)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestCaptureHoldTxFullAmount(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	hold := createRandomHold(t, account1, account2, 100)
+
+	result, err := testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(100), result.Hold.CapturedAmount.Int64)
+	require.Zero(t, result.Transfer.FromAccount.Balance)
+	require.Equal(t, int64(100), result.Transfer.ToAccount.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestVoidHoldTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	hold := createRandomHold(t, account1, account2, 60)
+
+	result, err := testStore.VoidHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusVoided, result.Hold.Status)
+	require.False(t, result.Hold.CapturedAmount.Valid)
+	require.Equal(t, int64(100), result.Account.Balance)
+	require.Zero(t, result.Account.HeldBalance)
+
+	_, err = testStore.VoidHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	_, err = testStore.VoidHoldTx(context.Background(), hold.ID+1_000_000)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestExpireHoldsTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	result, err := testStore.CreateHoldTx(context.Background(), CreateHoldTxParams{
+		AccountID:   account1.AccountID,
+		ToAccountID: account2.AccountID,
+		Amount:      60,
+		ExpiresAt:   time.Now().Add(-time.Second),
+	})
+	require.NoError(t, err)
+	hold := result.Hold
+
+	_, err = testStore.CaptureHoldTx(context.Background(), CaptureHoldTxParams{HoldID: hold.ID})
+	require.ErrorIs(t, err, ErrHoldNotActive)
+
+	// other tests may leave expired holds behind, so expire until this one is gone
+	for {
+		n, err := testStore.ExpireHoldsTx(context.Background(), 100)
+		require.NoError(t, err)
+
+		hold, err = testQueries.GetHold(context.Background(), hold.ID)
+		require.NoError(t, err)
+		if hold.Status != HoldStatusActive || n == 0 {
+			break
+		}
+	}
+	require.Equal(t, HoldStatusExpired, hold.Status)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Zero(t, account.HeldBalance)
+	require.Equal(t, int64(100), account.Balance)
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MultiLegCredit is an account credited by a multi-leg transfer
+type MultiLegCredit struct {
+	AccountID int64  `json:"account_id"`
+	Amount    int64  `json:"amount"`
+	Memo      string `json:"memo"`
+}
+
+// MultiLegTransferTxParams contains the input parameters for paying several accounts from one account
+type MultiLegTransferTxParams struct {
+	FromAccountID int64            `json:"from_account_id"`
+	Credits       []MultiLegCredit `json:"credits"`
+}
+
+// MultiLegTransferTxResult is the result of a multi-leg transfer: the parent transaction, its legs in order,
+// starting with the debit of the source account, and the entry each leg posted
+type MultiLegTransferTxResult struct {
+	Transaction Transaction      `json:"transaction"`
+	Legs        []TransactionLeg `json:"legs"`
+	Entries     []Entry          `json:"entries"`
+	FromAccount Account          `json:"from_account"`
+}
+
+// MultiLegTransferTx debits the source account by the sum of the credits and credits each account in one
+// transaction, recorded as a single parent transaction whose legs sum to zero. Every account is locked in
+// account_id order first, and the whole transfer is rejected when the available balance doesn't cover it.
+func (s *SQLStore) MultiLegTransferTx(ctx context.Context, args MultiLegTransferTxParams) (MultiLegTransferTxResult, error) {
+	var result MultiLegTransferTxResult
+	err := s.execTxWithRetry(ctx, "MultiLegTransferTx", func(q *Queries) error {
+		result = MultiLegTransferTxResult{}
+
+		var total int64
+		accountIDs := []int64{args.FromAccountID}
+		for _, credit := range args.Credits {
+			total += credit.Amount
+			accountIDs = append(accountIDs, credit.AccountID)
+		}
+
+		accounts, err := lockAccountsByID(ctx, q, accountIDs)
+		if err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+
+		fromAccount := accounts[args.FromAccountID]
+		if available := fromAccount.Balance - fromAccount.HeldBalance; available < total {
+			return &InsufficientFundsError{
+				AccountID: args.FromAccountID,
+				Available: available,
+				Requested: total,
+			}
+		}
+
+		result.Transaction, err = q.CreateMultiLegTransaction(ctx, CreateMultiLegTransactionParams{
+			SourceAccountID: args.FromAccountID,
+			Amount:          total,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		legs := []MultiLegCredit{{AccountID: args.FromAccountID, Amount: -total}}
+		for i, leg := range append(legs, args.Credits...) {
+			account, err := q.UpdateBalance(ctx, UpdateBalanceParams{
+				AccountID: leg.AccountID,
+				Amount:    leg.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update balance of account %d: %w", leg.AccountID, err)
+			}
+			if i == 0 {
+				result.FromAccount = account
+			}
+
+			entry, err := q.CreateEntry(ctx, CreateEntryParams{
+				AccountID:     leg.AccountID,
+				TransactionID: pgtype.Int8{Int64: result.Transaction.ID, Valid: true},
+				Amount:        leg.Amount,
+				Balance:       account.Balance,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create entry: %w", err)
+			}
+			result.Entries = append(result.Entries, entry)
+
+			transactionLeg, err := q.CreateTransactionLeg(ctx, CreateTransactionLegParams{
+				TransactionID: result.Transaction.ID,
+				Position:      int32(i),
+				AccountID:     leg.AccountID,
+				Amount:        leg.Amount,
+				Memo:          leg.Memo,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create transaction leg: %w", err)
+			}
+			result.Legs = append(result.Legs, transactionLeg)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiLegTransferTx(t *testing.T) {
+	buyer := createRandomLedgerAccount(t, 1000)
+	seller := createRandomLedgerAccount(t, 0)
+	platform := createRandomLedgerAccount(t, 0)
+	tax := createRandomLedgerAccount(t, 0)
+
+	result, err := testStore.MultiLegTransferTx(context.Background(), MultiLegTransferTxParams{
+		FromAccountID: buyer.AccountID,
+		Credits: []MultiLegCredit{
+			{AccountID: seller.AccountID, Amount: 850, Memo: "seller"},
+			{AccountID: platform.AccountID, Amount: 100, Memo: "platform fee"},
+			{AccountID: tax.AccountID, Amount: 50, Memo: "tax"},
+		},
+	})
+	require.NoError(t, err)
+
+	transaction := result.Transaction
+	require.Equal(t, buyer.AccountID, transaction.SourceAccountID)
+	require.False(t, transaction.DestinationAccountID.Valid)
+	require.Equal(t, int64(1000), transaction.Amount)
+	require.Equal(t, int64(0), result.FromAccount.Balance)
+
+	// the debit comes first and the legs sum to zero
+	require.Len(t, result.Legs, 4)
+	require.Equal(t, buyer.AccountID, result.Legs[0].AccountID)
+	require.Equal(t, int64(-1000), result.Legs[0].Amount)
+	var sum int64
+	for i, leg := range result.Legs {
+		require.Equal(t, transaction.ID, leg.TransactionID)
+		require.Equal(t, int32(i), leg.Position)
+		sum += leg.Amount
+
+		entry := result.Entries[i]
+		require.Equal(t, leg.AccountID, entry.AccountID)
+		require.Equal(t, leg.Amount, entry.Amount)
+		require.Equal(t, transaction.ID, entry.TransactionID.Int64)
+	}
+	require.Zero(t, sum)
+	require.Equal(t, "platform fee", result.Legs[2].Memo)
+
+	legs, err := testQueries.ListTransactionLegs(context.Background(), transaction.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Legs, legs)
+
+	requireBalance(t, seller.AccountID, 850)
+	requireBalance(t, platform.AccountID, 100)
+	requireBalance(t, tax.AccountID, 50)
+	requireLedgerConsistent(t, buyer.AccountID, seller.AccountID, platform.AccountID, tax.AccountID)
+
+	// the transaction is outgoing for the buyer and incoming for every recipient, each seeing its own amount
+	for accountID, amount := range map[int64]int64{buyer.AccountID: 1000, seller.AccountID: 850, tax.AccountID: 50} {
+		outgoing := accountID == buyer.AccountID
+		transactions, err := testQueries.ListAccountTransactions(context.Background(), ListAccountTransactionsParams{
+			AccountID:       accountID,
+			IncludeOutgoing: outgoing,
+			IncludeIncoming: !outgoing,
+			PageLimit:       10,
+		})
+		require.NoError(t, err)
+		require.Len(t, transactions, 1)
+		require.Equal(t, transaction.ID, transactions[0].Transaction.ID)
+		require.Equal(t, amount, transactions[0].AccountAmount)
+	}
+
+	// amount filters apply to the credit of the account, not the total paid
+	for _, filter := range []struct {
+		min, max int64
+		found    bool
+	}{
+		{min: 40, max: 60, found: true},
+		{min: 100, max: 1000, found: false},
+	} {
+		transactions, err := testQueries.ListAccountTransactions(context.Background(), ListAccountTransactionsParams{
+			AccountID:       tax.AccountID,
+			IncludeIncoming: true,
+			MinAmount:       pgtype.Int8{Int64: filter.min, Valid: true},
+			MaxAmount:       pgtype.Int8{Int64: filter.max, Valid: true},
+			PageLimit:       10,
+		})
+		require.NoError(t, err)
+		if filter.found {
+			require.Len(t, transactions, 1)
+		} else {
+			require.Empty(t, transactions)
+		}
+	}
+
+	byTransaction, err := testQueries.ListTransactionLegsByTransactions(context.Background(), []int64{transaction.ID})
+	require.NoError(t, err)
+	require.Equal(t, legs, byTransaction)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: transaction.ID,
+		Reason:        "refund",
+	})
+	require.ErrorIs(t, err, ErrReversalNotAllowed)
+}
+
+func TestMultiLegTransferTxInsufficientFunds(t *testing.T) {
+	buyer := createRandomLedgerAccount(t, 100)
+	seller := createRandomLedgerAccount(t, 0)
+	platform := createRandomLedgerAccount(t, 0)
+
+	_, err := testStore.MultiLegTransferTx(context.Background(), MultiLegTransferTxParams{
+		FromAccountID: buyer.AccountID,
+		Credits: []MultiLegCredit{
+			{AccountID: seller.AccountID, Amount: 90},
+			{AccountID: platform.AccountID, Amount: 20},
+		},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, int64(100), fundsErr.Available)
+	require.Equal(t, int64(110), fundsErr.Requested)
+
+	requireBalance(t, buyer.AccountID, 100)
+	requireBalance(t, seller.AccountID, 0)
+	requireBalance(t, platform.AccountID, 0)
+	requireLedgerConsistent(t, buyer.AccountID, seller.AccountID, platform.AccountID)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// TxConfig controls the isolation level transfers run at and how often they are retried
+// after Postgres aborts them with a serialization failure or a deadlock.
+type TxConfig struct {
+	IsoLevel       pgx.TxIsoLevel
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+// NewTxConfig reads the transaction settings from the config
+func NewTxConfig(config util.Config) (TxConfig, error) {
+	isoLevel, err := parseIsoLevel(config.TransferIsolationLevel)
+	if err != nil {
+		return TxConfig{}, err
+	}
+
+	if config.TxMaxRetries < 0 {
+		return TxConfig{}, fmt.Errorf("invalid TX_MAX_RETRIES %d: must not be negative", config.TxMaxRetries)
+	}
+
+	return TxConfig{
+		IsoLevel:       isoLevel,
+		MaxRetries:     config.TxMaxRetries,
+		RetryBaseDelay: config.TxRetryBaseDelay,
+		RetryMaxDelay:  config.TxRetryMaxDelay,
+	}, nil
+}
+
+// parseIsoLevel accepts the isolation levels transfers can run at, such as "repeatable_read".
+// An empty level keeps the database default.
+func parseIsoLevel(level string) (pgx.TxIsoLevel, error) {
+	switch isoLevel := pgx.TxIsoLevel(strings.ReplaceAll(strings.ToLower(level), "_", " ")); isoLevel {
+	case "", pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable:
+		return isoLevel, nil
+	}
+	return "", fmt.Errorf("unsupported transaction isolation level %q", level)
+}
+
+// backoff returns a random delay of up to RetryBaseDelay * 2^retry, capped at RetryMaxDelay
+func (config TxConfig) backoff(retry int) time.Duration {
+	delay := config.RetryBaseDelay << retry
+	if config.RetryMaxDelay > 0 && (delay > config.RetryMaxDelay || delay < config.RetryBaseDelay) {
+		delay = config.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return rand.N(delay) + 1
+}
+
+// execTxWithRetry runs fn in a transaction at the configured isolation level. When Postgres aborts
+// the transaction because of a concurrent one, fn runs again in a new transaction after a jittered
+// backoff, up to MaxRetries times.
+func (store *SQLStore) execTxWithRetry(ctx context.Context, name string, fn func(*Queries) error) error {
+	txOptions := pgx.TxOptions{IsoLevel: store.txConfig.IsoLevel}
+
+	for retry := 0; ; retry++ {
+		err := store.execTx(ctx, txOptions, fn)
+		if err == nil {
+			if retry > 0 {
+				log.Info().Str("tx", name).Int("retries", retry).Msg("transaction succeeded after retries")
+			}
+			return nil
+		}
+
+		if !errors.Is(err, ErrSerializationFailure) {
+			return err
+		}
+
+		if retry >= store.txConfig.MaxRetries {
+			log.Error().Err(err).Str("tx", name).Int("retries", retry).Msg("transaction failed after retries")
+			return err
+		}
+
+		delay := store.txConfig.backoff(retry)
+		log.Warn().Err(err).Str("tx", name).Int("retry", retry+1).Dur("delay", delay).Msg("retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
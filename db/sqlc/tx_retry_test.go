@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIsoLevel(t *testing.T) {
+	testCases := map[string]pgx.TxIsoLevel{
+		"":                "",
+		"read_committed":  pgx.ReadCommitted,
+		"repeatable_read": pgx.RepeatableRead,
+		"SERIALIZABLE":    pgx.Serializable,
+	}
+
+	for level, want := range testCases {
+		got, err := parseIsoLevel(level)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+
+	_, err := parseIsoLevel("read_uncommitted")
+	require.Error(t, err)
+}
+
+func TestBackoff(t *testing.T) {
+	config := TxConfig{RetryBaseDelay: 10 * time.Millisecond, RetryMaxDelay: 50 * time.Millisecond}
+
+	for retry := 0; retry < 70; retry++ {
+		limit := config.RetryBaseDelay << retry
+		if limit > config.RetryMaxDelay || limit < config.RetryBaseDelay {
+			limit = config.RetryMaxDelay
+		}
+
+		delay := config.backoff(retry)
+		require.Positive(t, delay)
+		require.LessOrEqual(t, delay, limit)
+	}
+
+	require.Zero(t, TxConfig{}.backoff(3))
+}
+
+func TestTransferTxRetriesAtRepeatableRead(t *testing.T) {
+	store := NewStore(testDB, TxConfig{
+		IsoLevel:       pgx.RepeatableRead,
+		MaxRetries:     50,
+		RetryBaseDelay: time.Millisecond,
+		RetryMaxDelay:  20 * time.Millisecond,
+	})
+
+	account1 := createRandomLedgerAccount(t, 1000)
+	account2 := createRandomLedgerAccount(t, 1000)
+
+	n := 10
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		fromAccountID, toAccountID := account1.AccountID, account2.AccountID
+		if i%2 == 1 {
+			fromAccountID, toAccountID = toAccountID, fromAccountID
+		}
+
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: fromAccountID,
+				ToAccountID:   toAccountID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ReverseTransferTxParams contains the input parameters for reversing a transaction
+type ReverseTransferTxParams struct {
+	TransactionID int64 `json:"transaction_id"`
+	// Amount to refund; 0 refunds everything not reversed yet
+	Amount int64  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+// ReverseTransferTxResult contains the reversed transaction and the compensating transfer
+type ReverseTransferTxResult struct {
+	OriginalTransaction Transaction      `json:"original_transaction"`
+	Reversal            TransferTxResult `json:"reversal"`
+}
+
+// ReverseTransferTx refunds all or part of a transaction by transferring the amount back from its destination
+// to its source account. The reversals of a transaction can't refund more than its amount in total,
+// and neither a reversal nor a multi-leg transaction can be reversed. Amounts are in the currency of the source account.
+func (s *SQLStore) ReverseTransferTx(ctx context.Context, args ReverseTransferTxParams) (ReverseTransferTxResult, error) {
+	var result ReverseTransferTxResult
+	err := s.execTxWithRetry(ctx, "ReverseTransferTx", func(q *Queries) error {
+		// lock the original first so concurrent reversals see each other's refunds
+		original, err := q.GetTransactionForUpdate(ctx, args.TransactionID)
+		if err != nil {
+			return fmt.Errorf("failed to lock transaction: %w", err)
+		}
+
+		if original.OriginalTransactionID.Valid {
+			return fmt.Errorf("%w: transaction %d reverses transaction %d", ErrReversalNotAllowed, original.ID, original.OriginalTransactionID.Int64)
+		}
+
+		if !original.DestinationAccountID.Valid {
+			return fmt.Errorf("%w: transaction %d has multiple legs", ErrReversalNotAllowed, original.ID)
+		}
+
+		remaining := original.Amount - original.ReversedAmount
+		amount := args.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining || remaining == 0 {
+			return fmt.Errorf("%w: %d left to reverse, requested %d", ErrReversalAmountExceeded, remaining, amount)
+		}
+
+		fromAccount, err := lockAccounts(ctx, q, original.DestinationAccountID.Int64, original.SourceAccountID)
+		if err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+
+		arg := CreateTransactionParams{
+			SourceAccountID:       original.DestinationAccountID.Int64,
+			DestinationAccountID:  original.SourceAccountID,
+			Amount:                amount,
+			OriginalTransactionID: pgtype.Int8{Int64: original.ID, Valid: true},
+			Reason:                pgtype.Text{String: args.Reason, Valid: true},
+		}
+
+		// a conversion is refunded at its original rate: the destination gives back the same share
+		// of what it was credited, and the reversals add up to exactly that once all is refunded
+		if rate, ok := original.AppliedRate(); ok {
+			credited := original.DestinationAmount.Int64
+			arg.Amount = prorate(credited, original.ReversedAmount+amount, original.Amount) -
+				prorate(credited, original.ReversedAmount, original.Amount)
+			arg.setConversion(rate.Invert(), amount)
+
+			// the insert would fail its amount check; say why before anything is written
+			if arg.Amount <= 0 {
+				return fmt.Errorf("%w: refunding %d gives back no %d of the converted amount", ErrReversalAmountTooSmall, amount, credited)
+			}
+		}
+
+		result.Reversal, err = transfer(ctx, q, fromAccount, arg)
+		if err != nil {
+			return err
+		}
+
+		result.OriginalTransaction, err = q.AddReversedAmount(ctx, AddReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update reversed amount: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransfer(t *testing.T, from, to Account, amount int64) Transaction {
+	result, err := testStore.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: from.AccountID,
+		ToAccountID:   to.AccountID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	return result.Transaction
+}
+
+func TestReverseTransferTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	original := createRandomTransfer(t, account1, account2, 60)
+
+	result, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.ID,
+		Amount:        25,
+		Reason:        "damaged item",
+	})
+	require.NoError(t, err)
+
+	reversal := result.Reversal.Transaction
+	require.Equal(t, account2.AccountID, reversal.SourceAccountID)
+	require.Equal(t, account1.AccountID, reversal.DestinationAccountID.Int64)
+	require.Equal(t, int64(25), reversal.Amount)
+	require.Equal(t, original.ID, reversal.OriginalTransactionID.Int64)
+	require.Equal(t, "damaged item", reversal.Reason.String)
+
+	require.Equal(t, int64(25), result.OriginalTransaction.ReversedAmount)
+	require.Equal(t, int64(65), result.Reversal.ToAccount.Balance)
+	require.Equal(t, int64(35), result.Reversal.FromAccount.Balance)
+
+	// only the 35 left can still be refunded
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.ID,
+		Amount:        36,
+		Reason:        "too much",
+	})
+	require.ErrorIs(t, err, ErrReversalAmountExceeded)
+
+	result, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.ID,
+		Reason:        "rest of the order",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(35), result.Reversal.Transaction.Amount)
+	require.Equal(t, original.Amount, result.OriginalTransaction.ReversedAmount)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.ID,
+		Reason:        "again",
+	})
+	require.ErrorIs(t, err, ErrReversalAmountExceeded)
+
+	_, err = testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: result.Reversal.Transaction.ID,
+		Reason:        "undo the refund",
+	})
+	require.ErrorIs(t, err, ErrReversalNotAllowed)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestReverseTransferTxConcurrent(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	original := createRandomTransfer(t, account1, account2, 50)
+
+	n := 8
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+				TransactionID: original.ID,
+				Amount:        10,
+				Reason:        "refund",
+			})
+			errs <- err
+		}()
+	}
+
+	succeeded := 0
+	for i := 0; i < n; i++ {
+		err := <-errs
+		if err == nil {
+			succeeded++
+			continue
+		}
+		require.ErrorIs(t, err, ErrReversalAmountExceeded)
+	}
+	require.Equal(t, 5, succeeded)
+
+	transaction, err := testQueries.GetTransaction(context.Background(), original.ID)
+	require.NoError(t, err)
+	require.Equal(t, original.Amount, transaction.ReversedAmount)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestReverseTransferTxInsufficientFunds(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	account3 := createRandomLedgerAccount(t, 0)
+	original := createRandomTransfer(t, account1, account2, 60)
+
+	// the destination already spent the money
+	createRandomTransfer(t, account2, account3, 50)
+
+	_, err := testStore.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransactionID: original.ID,
+		Reason:        "refund",
+	})
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, account2.AccountID, fundsErr.AccountID)
+	require.Equal(t, int64(10), fundsErr.Available)
+}
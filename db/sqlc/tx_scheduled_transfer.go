@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a scheduled transfer; only pending transfers are executed or can be canceled
+const (
+	ScheduledTransferStatusPending  = "pending"
+	ScheduledTransferStatusExecuted = "executed"
+	ScheduledTransferStatusFailed   = "failed"
+	ScheduledTransferStatusCanceled = "canceled"
+)
+
+// ExecuteScheduledTransfersResult counts the scheduled transfers a run of the executor resolved
+type ExecuteScheduledTransfersResult struct {
+	Executed int
+	Failed   int
+}
+
+// CancelScheduledTransferTx cancels a pending scheduled transfer, so it is never executed
+func (s *SQLStore) CancelScheduledTransferTx(ctx context.Context, id int64) (ScheduledTransfer, error) {
+	var result ScheduledTransfer
+	err := s.execTxWithRetry(ctx, "CancelScheduledTransferTx", func(q *Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock scheduled transfer: %w", err)
+		}
+
+		if scheduled.Status != ScheduledTransferStatusPending {
+			return fmt.Errorf("%w: scheduled transfer %d is %s", ErrScheduledTransferNotPending, id, scheduled.Status)
+		}
+
+		result, err = q.ResolveScheduledTransfer(ctx, ResolveScheduledTransferParams{
+			ID:     id,
+			Status: ScheduledTransferStatusCanceled,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve scheduled transfer: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// ExecuteScheduledTransfersTx runs up to limit pending transfers that are due, oldest first, each in its own
+// transaction that keeps the scheduled transfer locked while TransferTx moves the money. Workers running
+// concurrently skip the transfers locked by one another. A transfer that fails is marked failed with the
+// reason, so it doesn't hold up the ones due after it: the rejection for one the store rejects, such as for
+// insufficient funds, and executionErrorReason for any other error, which is returned once the run is over.
+func (s *SQLStore) ExecuteScheduledTransfersTx(ctx context.Context, limit int32) (ExecuteScheduledTransfersResult, error) {
+	var result ExecuteScheduledTransfersResult
+	var runErrs []error
+
+	for range limit {
+		var scheduled ScheduledTransfer
+		err := s.execTxWithRetry(ctx, "ExecuteScheduledTransfersTx", func(q *Queries) error {
+			var err error
+			scheduled, err = q.ClaimDueScheduledTransfer(ctx)
+			if err != nil {
+				return err
+			}
+
+			transfer, err := transferTx(ctx, q, TransferTxParams{
+				FromAccountID: scheduled.FromAccountID,
+				ToAccountID:   scheduled.ToAccountID,
+				Amount:        scheduled.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = q.ResolveScheduledTransfer(ctx, ResolveScheduledTransferParams{
+				ID:            scheduled.ID,
+				Status:        ScheduledTransferStatusExecuted,
+				TransactionID: pgtype.Int8{Int64: transfer.Transaction.ID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to resolve scheduled transfer: %w", err)
+			}
+
+			return nil
+		})
+		switch {
+		case err == nil:
+			result.Executed++
+			continue
+		case scheduled.ID == 0 && errors.Is(err, ErrRecordNotFound):
+			// no pending transfer is due
+			return result, errors.Join(runErrs...)
+		case scheduled.ID == 0 || ctx.Err() != nil:
+			// nothing was claimed, or the run is stopping: the next run picks up where this one stopped
+			runErrs = append(runErrs, fmt.Errorf("failed to execute scheduled transfer: %w", err))
+			return result, errors.Join(runErrs...)
+		case !isTransferRejection(err):
+			runErrs = append(runErrs, fmt.Errorf("failed to execute scheduled transfer %d: %w", scheduled.ID, err))
+		}
+
+		failed, failErr := s.failScheduledTransfer(ctx, scheduled.ID, failureReason(err))
+		if failErr != nil {
+			runErrs = append(runErrs, fmt.Errorf("failed to record failure of scheduled transfer %d: %w", scheduled.ID, failErr))
+			return result, errors.Join(runErrs...)
+		}
+		if failed {
+			result.Failed++
+		}
+	}
+
+	return result, errors.Join(runErrs...)
+}
+
+// transferRejections are the errors the store rejects a transfer with for a reason that retrying won't fix
+var transferRejections = []error{
+	ErrInsufficientFunds,
+	ErrRecordNotFound,
+	ErrForeignKeyViolation,
+	ErrCheckViolation,
+	ErrAmountOutOfRange,
+}
+
+// isTransferRejection reports whether the store rejected a transfer for a reason that retrying won't fix
+func isTransferRejection(err error) bool {
+	return rejectionReason(err) != ""
+}
+
+// rejectionReason returns the message of the rejection a transfer failed with, or "" if it wasn't rejected.
+// Unlike the error itself, it names no constraint, column or balance, so it can be stored and shown to users.
+func rejectionReason(err error) string {
+	for _, rejection := range transferRejections {
+		if errors.Is(err, rejection) {
+			return rejection.Error()
+		}
+	}
+	return ""
+}
+
+// executionErrorReason is the failure reason recorded for a transfer that failed with an error other than
+// a rejection. The error itself is returned to be logged, as it may name database internals.
+const executionErrorReason = "internal error"
+
+// failureReason returns the reason recorded for a transfer that failed with err
+func failureReason(err error) string {
+	if reason := rejectionReason(err); reason != "" {
+		return reason
+	}
+	return executionErrorReason
+}
+
+// failScheduledTransfer marks a scheduled transfer failed with reason, unless another worker resolved it first
+func (s *SQLStore) failScheduledTransfer(ctx context.Context, id int64, reason string) (bool, error) {
+	failed := false
+	err := s.execTxWithRetry(ctx, "ExecuteScheduledTransfersTx", func(q *Queries) error {
+		scheduled, err := q.GetScheduledTransferForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock scheduled transfer: %w", err)
+		}
+
+		if scheduled.Status != ScheduledTransferStatusPending {
+			return nil
+		}
+
+		_, err = q.ResolveScheduledTransfer(ctx, ResolveScheduledTransferParams{
+			ID:            id,
+			Status:        ScheduledTransferStatusFailed,
+			FailureReason: pgtype.Text{String: reason, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resolve scheduled transfer: %w", err)
+		}
+
+		failed = true
+		return nil
+	})
+
+	return failed, err
+}
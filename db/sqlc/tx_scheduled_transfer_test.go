@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomScheduledTransfer(t *testing.T, account, toAccount Account, amount int64, executeAt time.Time) ScheduledTransfer {
+	scheduled, err := testQueries.CreateScheduledTransfer(context.Background(), CreateScheduledTransferParams{
+		FromAccountID: account.AccountID,
+		ToAccountID:   toAccount.AccountID,
+		Amount:        amount,
+		ExecuteAt:     pgtype.Timestamptz{Time: executeAt, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, scheduled.ID)
+	require.Equal(t, ScheduledTransferStatusPending, scheduled.Status)
+	require.Equal(t, amount, scheduled.Amount)
+	require.False(t, scheduled.ResolvedAt.Valid)
+
+	return scheduled
+}
+
+// executeScheduledTransfers runs the executor until the scheduled transfer is resolved,
+// since other tests may leave due transfers behind
+func executeScheduledTransfers(t *testing.T, id int64) ScheduledTransfer {
+	for {
+		result, err := testStore.ExecuteScheduledTransfersTx(context.Background(), 100)
+		require.NoError(t, err)
+
+		scheduled, err := testQueries.GetScheduledTransfer(context.Background(), id)
+		require.NoError(t, err)
+		if scheduled.Status != ScheduledTransferStatusPending || result.Executed+result.Failed == 0 {
+			return scheduled
+		}
+	}
+}
+
+func TestExecuteScheduledTransfersTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	due := createRandomScheduledTransfer(t, account1, account2, 60, time.Now().Add(-time.Second))
+	later := createRandomScheduledTransfer(t, account1, account2, 10, time.Now().Add(time.Hour))
+
+	scheduled := executeScheduledTransfers(t, due.ID)
+	require.Equal(t, ScheduledTransferStatusExecuted, scheduled.Status)
+	require.True(t, scheduled.TransactionID.Valid)
+	require.False(t, scheduled.FailureReason.Valid)
+	require.True(t, scheduled.ResolvedAt.Valid)
+
+	transaction, err := testQueries.GetTransaction(context.Background(), scheduled.TransactionID.Int64)
+	require.NoError(t, err)
+	require.Equal(t, int64(60), transaction.Amount)
+
+	// transfers that aren't due yet are left pending
+	later, err = testQueries.GetScheduledTransfer(context.Background(), later.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusPending, later.Status)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestExecuteScheduledTransfersTxInsufficientFunds(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 50)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	due := createRandomScheduledTransfer(t, account1, account2, 60, time.Now().Add(-time.Second))
+
+	scheduled := executeScheduledTransfers(t, due.ID)
+	require.Equal(t, ScheduledTransferStatusFailed, scheduled.Status)
+	require.False(t, scheduled.TransactionID.Valid)
+	require.Equal(t, ErrInsufficientFunds.Error(), scheduled.FailureReason.String)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), account.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestCancelScheduledTransferTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	scheduled := createRandomScheduledTransfer(t, account1, account2, 60, time.Now().Add(-time.Second))
+
+	canceled, err := testStore.CancelScheduledTransferTx(context.Background(), scheduled.ID)
+	require.NoError(t, err)
+	require.Equal(t, ScheduledTransferStatusCanceled, canceled.Status)
+	require.True(t, canceled.ResolvedAt.Valid)
+
+	_, err = testStore.CancelScheduledTransferTx(context.Background(), scheduled.ID)
+	require.ErrorIs(t, err, ErrScheduledTransferNotPending)
+
+	// a canceled transfer is never executed
+	scheduled = executeScheduledTransfers(t, scheduled.ID)
+	require.Equal(t, ScheduledTransferStatusCanceled, scheduled.Status)
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+
+	_, err = testStore.CancelScheduledTransferTx(context.Background(), scheduled.ID+1_000_000)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
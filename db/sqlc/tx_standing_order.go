@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a standing order; only active orders run
+const (
+	StandingOrderStatusActive    = "active"
+	StandingOrderStatusCompleted = "completed"
+	StandingOrderStatusCanceled  = "canceled"
+)
+
+// Statuses of an execution of a standing order
+const (
+	StandingOrderExecutionStatusExecuted = "executed"
+	// StandingOrderExecutionStatusFailed is an attempt that is retried later
+	StandingOrderExecutionStatusFailed = "failed"
+	// StandingOrderExecutionStatusSkipped is an occurrence that is given up
+	StandingOrderExecutionStatusSkipped = "skipped"
+)
+
+// What a standing order does when the balance doesn't cover an occurrence
+const (
+	InsufficientFundsPolicySkip  = "skip"
+	InsufficientFundsPolicyRetry = "retry"
+)
+
+// ExecuteStandingOrdersTxParams contains the input parameters for running the standing orders that are due
+type ExecuteStandingOrdersTxParams struct {
+	Limit int32
+	// RetryInterval and MaxRetries apply to orders with the retry policy
+	RetryInterval time.Duration
+	MaxRetries    int32
+}
+
+// ExecuteStandingOrdersResult counts the executions a run of the standing orders recorded
+type ExecuteStandingOrdersResult struct {
+	Executed int
+	Failed   int
+	Skipped  int
+}
+
+// Total returns the number of executions recorded
+func (result ExecuteStandingOrdersResult) Total() int {
+	return result.Executed + result.Failed + result.Skipped
+}
+
+// CancelStandingOrderTx cancels an active standing order, so none of its remaining occurrences run
+func (s *SQLStore) CancelStandingOrderTx(ctx context.Context, id int64) (StandingOrder, error) {
+	var result StandingOrder
+	err := s.execTxWithRetry(ctx, "CancelStandingOrderTx", func(q *Queries) error {
+		order, err := q.GetStandingOrderForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to lock standing order: %w", err)
+		}
+
+		if order.Status != StandingOrderStatusActive {
+			return fmt.Errorf("%w: standing order %d is %s", ErrStandingOrderNotActive, id, order.Status)
+		}
+
+		result, err = q.UpdateStandingOrderRun(ctx, UpdateStandingOrderRunParams{
+			ID:          id,
+			Status:      StandingOrderStatusCanceled,
+			NextRunAt:   order.NextRunAt,
+			Retries:     order.Retries,
+			Occurrences: order.Occurrences,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update standing order: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// ExecuteStandingOrdersTx runs up to args.Limit occurrences of active standing orders that are due, most
+// overdue first, each in its own transaction that keeps the order locked while TransferTx moves the money.
+// Workers running concurrently skip the orders locked by one another. An occurrence the store rejects is
+// skipped, unless the order retries when funds are insufficient and has retries left; occurrences missed
+// while no worker ran are caught up one after the other. An occurrence that fails with any other error is
+// skipped with executionErrorReason, so the order doesn't hold up the ones due after it, and the error is
+// returned once the run is over.
+func (s *SQLStore) ExecuteStandingOrdersTx(ctx context.Context, args ExecuteStandingOrdersTxParams) (ExecuteStandingOrdersResult, error) {
+	var result ExecuteStandingOrdersResult
+	var runErrs []error
+
+	for range args.Limit {
+		var order StandingOrder
+		err := s.execTxWithRetry(ctx, "ExecuteStandingOrdersTx", func(q *Queries) error {
+			var err error
+			order, err = q.ClaimDueStandingOrder(ctx)
+			if err != nil {
+				return err
+			}
+
+			transfer, err := transferTx(ctx, q, TransferTxParams{
+				FromAccountID: order.FromAccountID,
+				ToAccountID:   order.ToAccountID,
+				Amount:        order.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = q.CreateStandingOrderExecution(ctx, CreateStandingOrderExecutionParams{
+				StandingOrderID: order.ID,
+				OccurrenceAt:    order.NextRunAt,
+				Status:          StandingOrderExecutionStatusExecuted,
+				TransactionID:   pgtype.Int8{Int64: transfer.Transaction.ID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create standing order execution: %w", err)
+			}
+
+			return advanceStandingOrder(ctx, q, order)
+		})
+		switch {
+		case err == nil:
+			result.Executed++
+			continue
+		case order.ID == 0 && errors.Is(err, ErrRecordNotFound):
+			// no active order is due
+			return result, errors.Join(runErrs...)
+		case order.ID == 0 || ctx.Err() != nil:
+			// nothing was claimed, or the run is stopping: the next run picks up where this one stopped
+			runErrs = append(runErrs, fmt.Errorf("failed to execute standing order: %w", err))
+			return result, errors.Join(runErrs...)
+		case !isTransferRejection(err):
+			runErrs = append(runErrs, fmt.Errorf("failed to execute standing order %d: %w", order.ID, err))
+		}
+
+		status, failErr := s.failStandingOrder(ctx, order, args, err)
+		if failErr != nil {
+			runErrs = append(runErrs, fmt.Errorf("failed to record failure of standing order %d: %w", order.ID, failErr))
+			return result, errors.Join(runErrs...)
+		}
+		switch status {
+		case StandingOrderExecutionStatusFailed:
+			result.Failed++
+		case StandingOrderExecutionStatusSkipped:
+			result.Skipped++
+		}
+	}
+
+	return result, errors.Join(runErrs...)
+}
+
+// failStandingOrder records that the occurrence of a claimed order failed with cause, and
+// returns the status of the execution it recorded. Nothing is recorded when another worker ran the order first.
+func (s *SQLStore) failStandingOrder(ctx context.Context, claimed StandingOrder, args ExecuteStandingOrdersTxParams, cause error) (string, error) {
+	status := ""
+	err := s.execTxWithRetry(ctx, "ExecuteStandingOrdersTx", func(q *Queries) error {
+		status = ""
+
+		order, err := q.GetStandingOrderForUpdate(ctx, claimed.ID)
+		if err != nil {
+			return fmt.Errorf("failed to lock standing order: %w", err)
+		}
+
+		if order.Status != StandingOrderStatusActive ||
+			!order.NextRunAt.Time.Equal(claimed.NextRunAt.Time) || order.Retries != claimed.Retries {
+			return nil
+		}
+
+		retry := order.InsufficientFundsPolicy == InsufficientFundsPolicyRetry &&
+			errors.Is(cause, ErrInsufficientFunds) && order.Retries < args.MaxRetries
+
+		status = StandingOrderExecutionStatusSkipped
+		if retry {
+			status = StandingOrderExecutionStatusFailed
+		}
+
+		_, err = q.CreateStandingOrderExecution(ctx, CreateStandingOrderExecutionParams{
+			StandingOrderID: order.ID,
+			OccurrenceAt:    order.NextRunAt,
+			Status:          status,
+			FailureReason:   pgtype.Text{String: failureReason(cause), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create standing order execution: %w", err)
+		}
+
+		if !retry {
+			return advanceStandingOrder(ctx, q, order)
+		}
+
+		_, err = q.UpdateStandingOrderRun(ctx, UpdateStandingOrderRunParams{
+			ID:          order.ID,
+			Status:      order.Status,
+			NextRunAt:   order.NextRunAt,
+			RetryAt:     pgtype.Timestamptz{Time: time.Now().Add(args.RetryInterval), Valid: true},
+			Retries:     order.Retries + 1,
+			Occurrences: order.Occurrences,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update standing order: %w", err)
+		}
+
+		return nil
+	})
+
+	return status, err
+}
+
+// advanceStandingOrder moves a locked order past its current occurrence, completing it once its end date
+// or maximum number of occurrences is reached
+func advanceStandingOrder(ctx context.Context, q *Queries, order StandingOrder) error {
+	schedule, err := util.ParseSchedule(order.Schedule, order.StartAt.Time)
+	if err != nil {
+		return fmt.Errorf("failed to parse schedule of standing order %d: %w", order.ID, err)
+	}
+
+	arg := UpdateStandingOrderRunParams{
+		ID:          order.ID,
+		Status:      StandingOrderStatusActive,
+		NextRunAt:   order.NextRunAt,
+		Occurrences: order.Occurrences + 1,
+	}
+
+	next := schedule.Next(order.NextRunAt.Time)
+	switch {
+	case order.MaxOccurrences.Valid && arg.Occurrences >= order.MaxOccurrences.Int32,
+		next.IsZero(),
+		order.EndAt.Valid && next.After(order.EndAt.Time):
+		arg.Status = StandingOrderStatusCompleted
+	default:
+		arg.NextRunAt = pgtype.Timestamptz{Time: next, Valid: true}
+	}
+
+	if _, err := q.UpdateStandingOrderRun(ctx, arg); err != nil {
+		return fmt.Errorf("failed to update standing order: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+var testStandingOrderParams = ExecuteStandingOrdersTxParams{
+	Limit:         100,
+	RetryInterval: time.Hour,
+	MaxRetries:    1,
+}
+
+func createRandomStandingOrder(t *testing.T, account, toAccount Account, amount int64, policy string, maxOccurrences int32) StandingOrder {
+	// started two days ago, so two daily occurrences are due
+	startAt := time.Now().Add(-48*time.Hour - time.Minute)
+
+	order, err := testQueries.CreateStandingOrder(context.Background(), CreateStandingOrderParams{
+		FromAccountID:           account.AccountID,
+		ToAccountID:             toAccount.AccountID,
+		Amount:                  amount,
+		Schedule:                util.ScheduleDaily,
+		StartAt:                 pgtype.Timestamptz{Time: startAt, Valid: true},
+		MaxOccurrences:          pgtype.Int4{Int32: maxOccurrences, Valid: maxOccurrences > 0},
+		InsufficientFundsPolicy: policy,
+		NextRunAt:               pgtype.Timestamptz{Time: startAt, Valid: true},
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, order.ID)
+	require.Equal(t, StandingOrderStatusActive, order.Status)
+	require.Zero(t, order.Occurrences)
+
+	return order
+}
+
+// executeStandingOrders runs the executor until the standing order has no occurrence due,
+// since other tests may leave due orders behind
+func executeStandingOrders(t *testing.T, id int64) StandingOrder {
+	for {
+		result, err := testStore.ExecuteStandingOrdersTx(context.Background(), testStandingOrderParams)
+		require.NoError(t, err)
+
+		order, err := testQueries.GetStandingOrder(context.Background(), id)
+		require.NoError(t, err)
+
+		due := order.NextRunAt.Time
+		if order.RetryAt.Valid {
+			due = order.RetryAt.Time
+		}
+		if order.Status != StandingOrderStatusActive || due.After(time.Now()) || result.Total() == 0 {
+			return order
+		}
+	}
+}
+
+func standingOrderExecutions(t *testing.T, id int64) []StandingOrderExecution {
+	executions, err := testQueries.ListStandingOrderExecutions(context.Background(), ListStandingOrderExecutionsParams{
+		StandingOrderID: id,
+		PageLimit:       100,
+	})
+	require.NoError(t, err)
+	return executions
+}
+
+func TestExecuteStandingOrdersTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	order := createRandomStandingOrder(t, account1, account2, 30, InsufficientFundsPolicySkip, 0)
+
+	// the missed occurrences are caught up, and the order waits for the next one
+	order = executeStandingOrders(t, order.ID)
+	require.Equal(t, StandingOrderStatusActive, order.Status)
+	require.Equal(t, int32(3), order.Occurrences)
+	require.True(t, order.NextRunAt.Time.After(time.Now()))
+
+	executions := standingOrderExecutions(t, order.ID)
+	require.Len(t, executions, 3)
+	for _, execution := range executions {
+		require.Equal(t, StandingOrderExecutionStatusExecuted, execution.Status)
+		require.True(t, execution.TransactionID.Valid)
+	}
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), account.Balance)
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestExecuteStandingOrdersTxMaxOccurrences(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	order := createRandomStandingOrder(t, account1, account2, 30, InsufficientFundsPolicySkip, 2)
+
+	order = executeStandingOrders(t, order.ID)
+	require.Equal(t, StandingOrderStatusCompleted, order.Status)
+	require.Equal(t, int32(2), order.Occurrences)
+	require.True(t, order.EndedAt.Valid)
+	require.Len(t, standingOrderExecutions(t, order.ID), 2)
+
+	_, err := testStore.CancelStandingOrderTx(context.Background(), order.ID)
+	require.ErrorIs(t, err, ErrStandingOrderNotActive)
+}
+
+func TestExecuteStandingOrdersTxSkip(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 50)
+	account2 := createRandomLedgerAccount(t, 0)
+	order := createRandomStandingOrder(t, account1, account2, 30, InsufficientFundsPolicySkip, 0)
+
+	order = executeStandingOrders(t, order.ID)
+	require.Equal(t, int32(3), order.Occurrences)
+
+	executions := standingOrderExecutions(t, order.ID)
+	require.Len(t, executions, 3)
+	require.Equal(t, StandingOrderExecutionStatusExecuted, executions[0].Status)
+	for _, execution := range executions[1:] {
+		require.Equal(t, StandingOrderExecutionStatusSkipped, execution.Status)
+		require.Equal(t, ErrInsufficientFunds.Error(), execution.FailureReason.String)
+	}
+
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestExecuteStandingOrdersTxRetry(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 10)
+	account2 := createRandomLedgerAccount(t, 0)
+	order := createRandomStandingOrder(t, account1, account2, 30, InsufficientFundsPolicyRetry, 0)
+
+	// the first occurrence fails and waits for a retry
+	order = executeStandingOrders(t, order.ID)
+	require.Equal(t, StandingOrderStatusActive, order.Status)
+	require.Zero(t, order.Occurrences)
+	require.Equal(t, int32(1), order.Retries)
+	require.True(t, order.RetryAt.Valid)
+
+	executions := standingOrderExecutions(t, order.ID)
+	require.Len(t, executions, 1)
+	require.Equal(t, StandingOrderExecutionStatusFailed, executions[0].Status)
+
+	canceled, err := testStore.CancelStandingOrderTx(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, StandingOrderStatusCanceled, canceled.Status)
+	require.True(t, canceled.EndedAt.Valid)
+}
+
+func TestCancelStandingOrderTx(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	order := createRandomStandingOrder(t, account1, account2, 30, InsufficientFundsPolicySkip, 0)
+
+	canceled, err := testStore.CancelStandingOrderTx(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, StandingOrderStatusCanceled, canceled.Status)
+	require.True(t, canceled.EndedAt.Valid)
+	require.Zero(t, canceled.Occurrences)
+
+	_, err = testStore.CancelStandingOrderTx(context.Background(), order.ID)
+	require.ErrorIs(t, err, ErrStandingOrderNotActive)
+
+	// a canceled order never runs, even with occurrences due
+	order = executeStandingOrders(t, order.ID)
+	require.Equal(t, StandingOrderStatusCanceled, order.Status)
+	require.Empty(t, standingOrderExecutions(t, order.ID))
+
+	account, err := testQueries.GetAccount(context.Background(), account1.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), account.Balance)
+
+	_, err = testStore.CancelStandingOrderTx(context.Background(), order.ID+1_000_000)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
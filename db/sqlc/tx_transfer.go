@@ -2,14 +2,39 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// ErrIdempotencyKeyInUse is returned by TransferTx when another live request already used the idempotency key
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is already in use")
+
 // TransferTxParams contains the input parameters for transferring money
 type TransferTxParams struct {
 	FromAccountID int64 `json:"from_account_id"`
 	ToAccountID   int64 `json:"to_account_id"`
 	Amount        int64 `json:"amount"`
+
+	// Conversion is optional; when set, the destination account is credited its DestinationAmount
+	Conversion *Conversion `json:"conversion,omitempty"`
+
+	// Idempotency is optional; when set, the result is stored under the key in the same transaction
+	Idempotency *IdempotencyParams `json:"-"`
+}
+
+// IdempotencyParams identifies a keyed request whose response must be replayed on retries
+type IdempotencyParams struct {
+	Username    string
+	Key         string
+	RequestHash string
+	ExpiresAt   time.Time
+	// RenderResponse encodes the response the client gets for the result, which is stored as is,
+	// so a retry replays exactly what the first request returned
+	RenderResponse func(TransferTxResult) ([]byte, error)
 }
 
 // TransferTxResult contains the result of a successful transfer transaction
@@ -17,62 +42,198 @@ type TransferTxResult struct {
 	Transaction Transaction `json:"transaction"`
 	FromAccount Account     `json:"from_account"`
 	ToAccount   Account     `json:"to_account"`
+	FromEntry   Entry       `json:"from_entry"`
+	ToEntry     Entry       `json:"to_entry"`
 }
 
 // TransferTx creates a transaction, updates balances, and returns updated accounts.
 func (s *SQLStore) TransferTx(ctx context.Context, args TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
-	err := s.execTx(ctx, func(q *Queries) error {
+	err := s.execTxWithRetry(ctx, "TransferTx", func(q *Queries) error {
 		var err error
+		result, err = transferTx(ctx, q, args)
+		return err
+	})
 
-		result.Transaction, err = q.CreateTransaction(ctx, CreateTransactionParams{
-			SourceAccountID:      args.FromAccountID,
-			DestinationAccountID: args.ToAccountID,
-			Amount:               args.Amount,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create transaction: %w", err)
-		}
+	return result, err
+}
 
-		if args.FromAccountID < args.ToAccountID {
-			err = updateBalances(ctx, q, args.FromAccountID, -args.Amount, args.ToAccountID, args.Amount)
-		} else {
-			err = updateBalances(ctx, q, args.ToAccountID, args.Amount, args.FromAccountID, -args.Amount)
-		}
-		if err != nil {
-			return fmt.Errorf("failed to update balances: %w", err)
-		}
+// transferTx runs a transfer within the transaction of q, locking both accounts
+func transferTx(ctx context.Context, q *Queries, args TransferTxParams) (TransferTxResult, error) {
+	fromAccount, err := lockAccounts(ctx, q, args.FromAccountID, args.ToAccountID)
+	if err != nil {
+		return TransferTxResult{}, fmt.Errorf("failed to lock accounts: %w", err)
+	}
 
-		result.FromAccount, err = q.GetAccount(ctx, args.FromAccountID)
-		if err != nil {
-			return fmt.Errorf("failed to fetch from_account: %w", err)
-		}
+	arg := CreateTransactionParams{
+		SourceAccountID:      args.FromAccountID,
+		DestinationAccountID: args.ToAccountID,
+		Amount:               args.Amount,
+	}
+	if args.Conversion != nil {
+		arg.setConversion(args.Conversion.Rate, args.Conversion.DestinationAmount)
+	}
 
-		result.ToAccount, err = q.GetAccount(ctx, args.ToAccountID)
-		if err != nil {
-			return fmt.Errorf("failed to fetch to_account: %w", err)
+	result, err := transfer(ctx, q, fromAccount, arg)
+	if err != nil {
+		return result, err
+	}
+
+	if args.Idempotency != nil {
+		return result, storeIdempotentResult(ctx, q, args.Idempotency, result)
+	}
+
+	return result, nil
+}
+
+// transfer moves money between two accounts locked by the caller, writing the transaction and both entries.
+// Only the available balance of the source account, net of its active holds, can be transferred.
+// A converted transaction credits its destination amount instead of its amount.
+func transfer(ctx context.Context, q *Queries, fromAccount Account, arg CreateTransactionParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	if available := fromAccount.Balance - fromAccount.HeldBalance; available < arg.Amount {
+		return result, &InsufficientFundsError{
+			AccountID: arg.SourceAccountID,
+			Available: available,
+			Requested: arg.Amount,
 		}
+	}
+
+	var err error
+	result.Transaction, err = q.CreateTransaction(ctx, arg)
+	if err != nil {
+		return result, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	credit := arg.Amount
+	if arg.DestinationAmount.Valid {
+		credit = arg.DestinationAmount.Int64
+	}
+
+	// update in the order the accounts were locked
+	if arg.SourceAccountID < arg.DestinationAccountID {
+		result.FromAccount, result.ToAccount, err = updateBalances(ctx, q, arg.SourceAccountID, -arg.Amount, arg.DestinationAccountID, credit)
+	} else {
+		result.ToAccount, result.FromAccount, err = updateBalances(ctx, q, arg.DestinationAccountID, credit, arg.SourceAccountID, -arg.Amount)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to update balances: %w", err)
+	}
+
+	transactionID := pgtype.Int8{Int64: result.Transaction.ID, Valid: true}
+
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:     arg.SourceAccountID,
+		TransactionID: transactionID,
+		Amount:        -arg.Amount,
+		Balance:       result.FromAccount.Balance,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to create from_entry: %w", err)
+	}
 
-		return nil
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:     arg.DestinationAccountID,
+		TransactionID: transactionID,
+		Amount:        credit,
+		Balance:       result.ToAccount.Balance,
 	})
+	if err != nil {
+		return result, fmt.Errorf("failed to create to_entry: %w", err)
+	}
 
-	return result, err
+	return result, nil
+}
+
+// storeIdempotentResult saves the response of the transfer so retries with the same key can replay it
+func storeIdempotentResult(ctx context.Context, q *Queries, params *IdempotencyParams, result TransferTxResult) error {
+	body, err := params.RenderResponse(result)
+	if err != nil {
+		return fmt.Errorf("failed to render transfer response: %w", err)
+	}
+
+	_, err = q.CreateIdempotencyKey(ctx, CreateIdempotencyKeyParams{
+		Username:     params.Username,
+		Key:          params.Key,
+		RequestHash:  params.RequestHash,
+		ResponseBody: body,
+		ExpiresAt:    pgtype.Timestamptz{Time: params.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrIdempotencyKeyInUse
+		}
+		return fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+
+	return nil
 }
 
-// updateBalances updates balances of two accounts atomically
-func updateBalances(ctx context.Context, q *Queries, acc1ID, amt1, acc2ID, amt2 int64) error {
-	_, err := q.UpdateBalance(ctx, UpdateBalanceParams{
+// lockAccounts locks both accounts of a transfer for the rest of the transaction and returns the source account.
+// Accounts are always locked in ascending order to avoid deadlocks between opposite transfers.
+func lockAccounts(ctx context.Context, q *Queries, fromAccountID, toAccountID int64) (Account, error) {
+	firstID, secondID := fromAccountID, toAccountID
+	if firstID > secondID {
+		firstID, secondID = secondID, firstID
+	}
+
+	first, err := q.GetAccountForUpdate(ctx, firstID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	second, err := q.GetAccountForUpdate(ctx, secondID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	if first.AccountID == fromAccountID {
+		return first, nil
+	}
+	return second, nil
+}
+
+// lockAccountsByID locks every account of accountIDs in account_id order, so transactions locking
+// overlapping sets of accounts can't deadlock, and returns them by account_id
+func lockAccountsByID(ctx context.Context, q *Queries, accountIDs []int64) (map[int64]Account, error) {
+	accountIDs = slices.Clone(accountIDs)
+	slices.Sort(accountIDs)
+	accountIDs = slices.Compact(accountIDs)
+
+	locked, err := q.GetAccountsForUpdate(ctx, accountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[int64]Account, len(locked))
+	for _, account := range locked {
+		accounts[account.AccountID] = account
+	}
+
+	for _, accountID := range accountIDs {
+		if _, ok := accounts[accountID]; !ok {
+			return nil, fmt.Errorf("account %d: %w", accountID, ErrRecordNotFound)
+		}
+	}
+
+	return accounts, nil
+}
+
+// updateBalances updates balances of two accounts atomically and returns the updated accounts
+func updateBalances(ctx context.Context, q *Queries, acc1ID, amt1, acc2ID, amt2 int64) (account1 Account, account2 Account, err error) {
+	account1, err = q.UpdateBalance(ctx, UpdateBalanceParams{
 		AccountID: acc1ID,
 		Amount:    amt1,
 	})
 	if err != nil {
-		return err
+		return
 	}
 
-	_, err = q.UpdateBalance(ctx, UpdateBalanceParams{
+	account2, err = q.UpdateBalance(ctx, UpdateBalanceParams{
 		AccountID: acc2ID,
 		Amount:    amt2,
 	})
 
-	return err
+	return
 }
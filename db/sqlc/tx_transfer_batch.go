@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Modes of a transfer batch
+const (
+	// TransferBatchModeAtomic runs every transfer of the batch in one transaction, or none of them
+	TransferBatchModeAtomic = "atomic"
+	// TransferBatchModeBestEffort runs each transfer of the batch in its own transaction
+	TransferBatchModeBestEffort = "best_effort"
+)
+
+// Statuses of a transfer batch; a batch is pending until it has run
+const (
+	TransferBatchStatusPending   = "pending"
+	TransferBatchStatusCompleted = "completed"
+	TransferBatchStatusPartial   = "partial"
+	TransferBatchStatusFailed    = "failed"
+)
+
+// Statuses of a transfer of a batch
+const (
+	TransferBatchItemStatusSucceeded = "succeeded"
+	TransferBatchItemStatusFailed    = "failed"
+)
+
+// BatchTransfer is one transfer of a batch
+type BatchTransfer struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	Amount        int64 `json:"amount"`
+}
+
+// TransferBatchTxParams contains the input parameters for running a batch of transfers
+type TransferBatchTxParams struct {
+	Owner     string          `json:"owner"`
+	Mode      string          `json:"mode"`
+	Transfers []BatchTransfer `json:"transfers"`
+}
+
+// TransferBatchTxResult contains a batch and the outcome of each of its transfers, in the order they were given
+type TransferBatchTxResult struct {
+	Batch TransferBatch       `json:"batch"`
+	Items []TransferBatchItem `json:"items"`
+}
+
+// TransferBatchTx runs a batch of transfers. In atomic mode they all run in a single transaction that first
+// locks every account involved in account_id order, so concurrent batches can't deadlock, and any failure
+// rolls back the whole batch. In best effort mode each transfer runs in its own transaction: a transfer the
+// store rejects, such as for insufficient funds, is recorded as failed with the reason and the batch moves
+// on, while any other error stops the batch. The batch itself is written outside the transactions of its
+// transfers, so it keeps its id and reaches a final status even when it fails; the result holds it along
+// with the error.
+func (s *SQLStore) TransferBatchTx(ctx context.Context, args TransferBatchTxParams) (TransferBatchTxResult, error) {
+	var result TransferBatchTxResult
+
+	batch, err := s.CreateTransferBatch(ctx, CreateTransferBatchParams{
+		Owner: args.Owner,
+		Mode:  args.Mode,
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to create transfer batch: %w", err)
+	}
+	result.Batch = batch
+
+	if args.Mode == TransferBatchModeAtomic {
+		return s.atomicTransferBatchTx(ctx, batch, args)
+	}
+	return s.bestEffortTransferBatchTx(ctx, batch, args)
+}
+
+func (s *SQLStore) atomicTransferBatchTx(ctx context.Context, batch TransferBatch, args TransferBatchTxParams) (TransferBatchTxResult, error) {
+	result := TransferBatchTxResult{Batch: batch}
+	failedAt := -1
+
+	err := s.execTxWithRetry(ctx, "TransferBatchTx", func(q *Queries) error {
+		result.Items = nil
+		failedAt = -1
+
+		accounts, err := lockBatchAccounts(ctx, q, args.Transfers)
+		if err != nil {
+			return fmt.Errorf("failed to lock accounts: %w", err)
+		}
+
+		for i, item := range args.Transfers {
+			transferred, err := transfer(ctx, q, accounts[item.FromAccountID], CreateTransactionParams{
+				SourceAccountID:      item.FromAccountID,
+				DestinationAccountID: item.ToAccountID,
+				Amount:               item.Amount,
+			})
+			if err != nil {
+				failedAt = i
+				return fmt.Errorf("transfer %d: %w", i, err)
+			}
+
+			// later transfers must see the balances this one left
+			accounts[item.FromAccountID] = transferred.FromAccount
+			accounts[item.ToAccountID] = transferred.ToAccount
+
+			batchItem, err := q.CreateTransferBatchItem(ctx, batchItemParams(batch.ID, i, item, transferred.Transaction.ID, nil))
+			if err != nil {
+				return fmt.Errorf("failed to create transfer batch item: %w", err)
+			}
+			result.Items = append(result.Items, batchItem)
+		}
+
+		result.Batch, err = q.CompleteTransferBatch(ctx, CompleteTransferBatchParams{
+			ID:     batch.ID,
+			Status: TransferBatchStatusCompleted,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to complete transfer batch: %w", err)
+		}
+
+		return nil
+	})
+	if err == nil {
+		return result, nil
+	}
+
+	// every transfer was rolled back: only the transfer the store rejected, if any, is recorded with the reason
+	result.Items = nil
+	if failedAt >= 0 && isTransferRejection(err) {
+		batchItem, itemErr := s.CreateTransferBatchItem(ctx, batchItemParams(batch.ID, failedAt, args.Transfers[failedAt], 0, err))
+		if itemErr != nil {
+			return result, errors.Join(err, fmt.Errorf("failed to create transfer batch item: %w", itemErr))
+		}
+		result.Items = append(result.Items, batchItem)
+	}
+
+	return s.completeTransferBatch(ctx, result, TransferBatchStatusFailed, err)
+}
+
+func (s *SQLStore) bestEffortTransferBatchTx(ctx context.Context, batch TransferBatch, args TransferBatchTxParams) (TransferBatchTxResult, error) {
+	result := TransferBatchTxResult{Batch: batch}
+
+	succeeded := 0
+	for i, item := range args.Transfers {
+		var batchItem TransferBatchItem
+		err := s.execTxWithRetry(ctx, "TransferBatchTx", func(q *Queries) error {
+			transfer, err := transferTx(ctx, q, TransferTxParams{
+				FromAccountID: item.FromAccountID,
+				ToAccountID:   item.ToAccountID,
+				Amount:        item.Amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			batchItem, err = q.CreateTransferBatchItem(ctx, batchItemParams(batch.ID, i, item, transfer.Transaction.ID, nil))
+			if err != nil {
+				return fmt.Errorf("failed to create transfer batch item: %w", err)
+			}
+
+			return nil
+		})
+		switch {
+		case err == nil:
+			succeeded++
+		case isTransferRejection(err):
+			batchItem, err = s.CreateTransferBatchItem(ctx, batchItemParams(batch.ID, i, item, 0, err))
+			if err != nil {
+				err = fmt.Errorf("failed to create transfer batch item: %w", err)
+				return s.completeTransferBatch(ctx, result, batchStatus(succeeded, len(args.Transfers)), err)
+			}
+		default:
+			// the transfers left are not run, so the batch ends with the outcome of those that were
+			err = fmt.Errorf("transfer %d: %w", i, err)
+			return s.completeTransferBatch(ctx, result, batchStatus(succeeded, len(args.Transfers)), err)
+		}
+		result.Items = append(result.Items, batchItem)
+	}
+
+	return s.completeTransferBatch(ctx, result, batchStatus(succeeded, len(args.Transfers)), nil)
+}
+
+// batchStatus returns the final status of a batch of total transfers of which succeeded did
+func batchStatus(succeeded, total int) string {
+	switch succeeded {
+	case total:
+		return TransferBatchStatusCompleted
+	case 0:
+		return TransferBatchStatusFailed
+	default:
+		return TransferBatchStatusPartial
+	}
+}
+
+// completeTransferBatch gives the batch of result its final status, returning cause, the error the batch
+// stopped on, if any, together with any error recording the status
+func (s *SQLStore) completeTransferBatch(ctx context.Context, result TransferBatchTxResult, status string, cause error) (TransferBatchTxResult, error) {
+	batch, err := s.CompleteTransferBatch(ctx, CompleteTransferBatchParams{
+		ID:     result.Batch.ID,
+		Status: status,
+	})
+	if err != nil {
+		return result, errors.Join(cause, fmt.Errorf("failed to complete transfer batch: %w", err))
+	}
+
+	result.Batch = batch
+	return result, cause
+}
+
+// batchItemParams records the outcome of a transfer of a batch: the transaction it created, or the rejection it failed with
+func batchItemParams(batchID int64, position int, item BatchTransfer, transactionID int64, transferErr error) CreateTransferBatchItemParams {
+	arg := CreateTransferBatchItemParams{
+		BatchID:       batchID,
+		Position:      int32(position),
+		FromAccountID: item.FromAccountID,
+		ToAccountID:   item.ToAccountID,
+		Amount:        item.Amount,
+		Status:        TransferBatchItemStatusSucceeded,
+		TransactionID: pgtype.Int8{Int64: transactionID, Valid: true},
+	}
+	if transferErr != nil {
+		arg.Status = TransferBatchItemStatusFailed
+		arg.TransactionID = pgtype.Int8{}
+		arg.Error = pgtype.Text{String: rejectionReason(transferErr), Valid: true}
+	}
+	return arg
+}
+
+// lockBatchAccounts locks every account the transfers involve and returns them by account_id
+func lockBatchAccounts(ctx context.Context, q *Queries, transfers []BatchTransfer) (map[int64]Account, error) {
+	var accountIDs []int64
+	for _, item := range transfers {
+		accountIDs = append(accountIDs, item.FromAccountID, item.ToAccountID)
+	}
+	return lockAccountsByID(ctx, q, accountIDs)
+}
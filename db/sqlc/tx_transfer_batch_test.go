@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireBalance(t *testing.T, accountID, balance int64) {
+	account, err := testQueries.GetAccount(context.Background(), accountID)
+	require.NoError(t, err)
+	require.Equal(t, balance, account.Balance)
+}
+
+func TestTransferBatchTxAtomic(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+	account3 := createRandomLedgerAccount(t, 0)
+
+	// the second transfer is only covered by the money the first one brings in
+	result, err := testStore.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		Owner: account1.Owner,
+		Mode:  TransferBatchModeAtomic,
+		Transfers: []BatchTransfer{
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 100},
+			{FromAccountID: account2.AccountID, ToAccountID: account3.AccountID, Amount: 60},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchStatusCompleted, result.Batch.Status)
+	require.True(t, result.Batch.CompletedAt.Valid)
+	require.Len(t, result.Items, 2)
+	for i, item := range result.Items {
+		require.Equal(t, int32(i), item.Position)
+		require.Equal(t, TransferBatchItemStatusSucceeded, item.Status)
+		require.True(t, item.TransactionID.Valid)
+	}
+
+	items, err := testQueries.ListTransferBatchItems(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Items, items)
+
+	requireBalance(t, account1.AccountID, 0)
+	requireBalance(t, account2.AccountID, 40)
+	requireBalance(t, account3.AccountID, 60)
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID, account3.AccountID)
+}
+
+func TestTransferBatchTxAtomicRollback(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	result, err := testStore.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		Owner: account1.Owner,
+		Mode:  TransferBatchModeAtomic,
+		Transfers: []BatchTransfer{
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 60},
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 60},
+		},
+	})
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	require.Equal(t, int64(40), fundsErr.Available)
+
+	// the batch outlives the rollback, failed with the transfer that was rejected
+	batch, err := testQueries.GetTransferBatch(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchStatusFailed, batch.Status)
+	require.True(t, batch.CompletedAt.Valid)
+	require.Equal(t, result.Batch, batch)
+
+	items, err := testQueries.ListTransferBatchItems(context.Background(), batch.ID)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, int32(1), items[0].Position)
+	require.Equal(t, TransferBatchItemStatusFailed, items[0].Status)
+	require.Equal(t, ErrInsufficientFunds.Error(), items[0].Error.String)
+	require.Equal(t, result.Items, items)
+
+	// the first transfer is rolled back with the second
+	transactions, err := testQueries.ListAccountTransactions(context.Background(), ListAccountTransactionsParams{
+		AccountID:       account1.AccountID,
+		IncludeOutgoing: true,
+		IncludeIncoming: true,
+		PageLimit:       10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, transactions)
+
+	requireBalance(t, account1.AccountID, 100)
+	requireBalance(t, account2.AccountID, 0)
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+}
+
+func TestTransferBatchTxBestEffort(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 100)
+	account2 := createRandomLedgerAccount(t, 0)
+
+	result, err := testStore.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		Owner: account1.Owner,
+		Mode:  TransferBatchModeBestEffort,
+		Transfers: []BatchTransfer{
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 60},
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 60},
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 40},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchStatusPartial, result.Batch.Status)
+	require.Len(t, result.Items, 3)
+
+	require.Equal(t, TransferBatchItemStatusSucceeded, result.Items[0].Status)
+	require.Equal(t, TransferBatchItemStatusFailed, result.Items[1].Status)
+	require.False(t, result.Items[1].TransactionID.Valid)
+	require.Equal(t, ErrInsufficientFunds.Error(), result.Items[1].Error.String)
+	require.Equal(t, TransferBatchItemStatusSucceeded, result.Items[2].Status)
+
+	batch, err := testQueries.GetTransferBatch(context.Background(), result.Batch.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Batch, batch)
+
+	requireBalance(t, account1.AccountID, 0)
+	requireBalance(t, account2.AccountID, 100)
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID)
+
+	result, err = testStore.TransferBatchTx(context.Background(), TransferBatchTxParams{
+		Owner: account1.Owner,
+		Mode:  TransferBatchModeBestEffort,
+		Transfers: []BatchTransfer{
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 1},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferBatchStatusFailed, result.Batch.Status)
+}
+
+func TestTransferBatchTxDeadlock(t *testing.T) {
+	account1 := createRandomLedgerAccount(t, 1000)
+	account2 := createRandomLedgerAccount(t, 1000)
+	account3 := createRandomLedgerAccount(t, 1000)
+
+	n := 10
+	errs := make(chan error)
+
+	// batches locking the same accounts in opposite directions
+	for i := 0; i < n; i++ {
+		transfers := []BatchTransfer{
+			{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 10},
+			{FromAccountID: account2.AccountID, ToAccountID: account3.AccountID, Amount: 10},
+			{FromAccountID: account3.AccountID, ToAccountID: account1.AccountID, Amount: 10},
+		}
+		if i%2 == 1 {
+			for j := range transfers {
+				transfers[j].FromAccountID, transfers[j].ToAccountID = transfers[j].ToAccountID, transfers[j].FromAccountID
+			}
+		}
+
+		go func() {
+			_, err := testStore.TransferBatchTx(context.Background(), TransferBatchTxParams{
+				Owner:     account1.Owner,
+				Mode:      TransferBatchModeAtomic,
+				Transfers: transfers,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	requireBalance(t, account1.AccountID, 1000)
+	requireBalance(t, account2.AccountID, 1000)
+	requireBalance(t, account3.AccountID, 1000)
+	requireLedgerConsistent(t, account1.AccountID, account2.AccountID, account3.AccountID)
+}
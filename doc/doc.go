@@ -0,0 +1,36 @@
+// Package doc serves the OpenAPI document generated from proto/ with Swagger UI.
+package doc
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"net/http"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed swagger
+var swagger embed.FS
+
+// SwaggerHandler serves Swagger UI together with the OpenAPI document.
+// Mount it with http.StripPrefix so that it sees paths relative to its root.
+func SwaggerHandler() http.Handler {
+	docs, _ := fs.Sub(swagger, "swagger")
+	return http.FileServer(http.FS(overlayFS{docs, swaggerFiles.FS}))
+}
+
+// overlayFS serves files from upper, falling back to lower,
+// so our swagger-initializer.js replaces the one shipped with Swagger UI.
+type overlayFS struct {
+	upper fs.FS
+	lower fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	file, err := o.upper.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o.lower.Open(name)
+	}
+	return file, err
+}
@@ -0,0 +1,158 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/chandiniv1/transfers-system/util"
+)
+
+// ErrRateNotFound is returned when no exchange rate is known between two currencies
+var ErrRateNotFound = errors.New("exchange rate not found")
+
+// RateProvider quotes the mid-market rate between two currencies
+type RateProvider interface {
+	Rate(ctx context.Context, from, to string) (Rate, error)
+}
+
+// Table quotes cross rates from the rates of every currency against a base currency
+type Table struct {
+	base  string
+	rates map[string]Rate
+}
+
+// NewTable creates a table from the amount of each currency paid for one unit of base
+func NewTable(base string, rates map[string]Rate) *Table {
+	table := &Table{
+		base:  base,
+		rates: make(map[string]Rate, len(rates)+1),
+	}
+	for currency, rate := range rates {
+		table.rates[currency] = rate
+	}
+	table.rates[base] = RateScale
+
+	return table
+}
+
+// rateFile is the layout of the file read by LoadTable:
+//
+//	{"base": "USD", "rates": {"EUR": "0.92", "CAD": "1.36"}}
+type rateFile struct {
+	Base  string            `json:"base"`
+	Rates map[string]string `json:"rates"`
+}
+
+// LoadTable reads a table of rates from a JSON file, for local use in place of a market data feed
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read exchange rates: %w", err)
+	}
+
+	var file rateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse exchange rates %s: %w", path, err)
+	}
+
+	if _, ok := util.LookupCurrency(file.Base); !ok {
+		return nil, fmt.Errorf("unsupported base currency %q in %s", file.Base, path)
+	}
+
+	rates := make(map[string]Rate, len(file.Rates))
+	for currency, value := range file.Rates {
+		if _, ok := util.LookupCurrency(currency); !ok {
+			return nil, fmt.Errorf("unsupported currency %q in %s", currency, path)
+		}
+
+		rate, err := ParseRate(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, currency, err)
+		}
+		rates[currency] = rate
+	}
+
+	return NewTable(file.Base, rates), nil
+}
+
+// Quotes reports whether the table has a rate for the currency
+func (table *Table) Quotes(currency string) bool {
+	_, ok := table.rates[currency]
+	return ok
+}
+
+// Rate returns the cross rate from one currency to another through the base currency
+func (table *Table) Rate(ctx context.Context, from, to string) (Rate, error) {
+	fromRate, ok := table.rates[from]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrRateNotFound, from, to)
+	}
+
+	toRate, ok := table.rates[to]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrRateNotFound, from, to)
+	}
+
+	scaled := new(big.Int).Mul(big.NewInt(int64(toRate)), big.NewInt(int64(RateScale)))
+	rate, ok := roundRat(new(big.Rat).SetFrac(scaled, big.NewInt(int64(fromRate))))
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("%w: %s to %s", ErrRateNotFound, from, to)
+	}
+
+	return Rate(rate), nil
+}
+
+// spreadProvider lowers the rates of another provider by a spread
+type spreadProvider struct {
+	provider  RateProvider
+	spreadBps int64
+}
+
+// WithSpread returns a provider quoting the rates of provider lowered by spreadBps basis points.
+// Exchanges within a single currency are quoted at RateScale, without a spread.
+func WithSpread(provider RateProvider, spreadBps int64) (RateProvider, error) {
+	if spreadBps < 0 || spreadBps >= maxSpreadBps {
+		return nil, fmt.Errorf("invalid spread of %d basis points", spreadBps)
+	}
+
+	return &spreadProvider{provider: provider, spreadBps: spreadBps}, nil
+}
+
+func (p *spreadProvider) Rate(ctx context.Context, from, to string) (Rate, error) {
+	if from == to {
+		return RateScale, nil
+	}
+
+	rate, err := p.provider.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+
+	return rate.WithSpread(p.spreadBps), nil
+}
+
+// NewProviderFromConfig builds the rate provider selected by the config, applying the configured spread.
+// Every enabled currency must have a rate, so that any two accounts can convert between each other.
+// It returns a nil provider when no rates are configured, which disables conversions.
+func NewProviderFromConfig(config util.Config, currencies *util.CurrencyRegistry) (RateProvider, error) {
+	if config.FXRatesPath == "" {
+		return nil, nil
+	}
+
+	table, err := LoadTable(config.FXRatesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, currency := range currencies.Currencies() {
+		if !table.Quotes(currency.Code) {
+			return nil, fmt.Errorf("enabled currency %s has no exchange rate in %s", currency.Code, config.FXRatesPath)
+		}
+	}
+
+	return WithSpread(table, config.FXSpreadBps)
+}
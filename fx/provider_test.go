@@ -0,0 +1,104 @@
+package fx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableRate(t *testing.T) {
+	table := NewTable(util.USD, map[string]Rate{
+		util.EUR: 92_000_000,
+		util.CAD: 136_000_000,
+	})
+
+	testCases := []struct {
+		from string
+		to   string
+		rate Rate
+	}{
+		{util.USD, util.USD, RateScale},
+		{util.USD, util.EUR, 92_000_000},
+		{util.EUR, util.USD, 108_695_652},
+		{util.EUR, util.CAD, 147_826_087},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.from+"/"+tc.to, func(t *testing.T) {
+			rate, err := table.Rate(context.Background(), tc.from, tc.to)
+			require.NoError(t, err)
+			require.Equal(t, tc.rate, rate)
+		})
+	}
+
+	_, err := table.Rate(context.Background(), util.USD, "GBP")
+	require.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestLoadTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "USD", "rates": {"EUR": "0.92"}}`), 0o600))
+
+	table, err := LoadTable(path)
+	require.NoError(t, err)
+
+	rate, err := table.Rate(context.Background(), util.USD, util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, Rate(92_000_000), rate)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "USD", "rates": {"EUR": "-1"}}`), 0o600))
+	_, err = LoadTable(path)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "XYZ", "rates": {}}`), 0o600))
+	_, err = LoadTable(path)
+	require.Error(t, err)
+
+	_, err = LoadTable(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestWithSpread(t *testing.T) {
+	table := NewTable(util.USD, map[string]Rate{util.EUR: 92_000_000})
+
+	provider, err := WithSpread(table, 50)
+	require.NoError(t, err)
+
+	rate, err := provider.Rate(context.Background(), util.USD, util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, Rate(91_540_000), rate)
+
+	rate, err = provider.Rate(context.Background(), util.EUR, util.EUR)
+	require.NoError(t, err)
+	require.Equal(t, RateScale, rate)
+
+	_, err = WithSpread(table, -1)
+	require.Error(t, err)
+
+	_, err = WithSpread(table, 10_000)
+	require.Error(t, err)
+}
+
+func TestNewProviderFromConfig(t *testing.T) {
+	currencies, err := util.NewCurrencyRegistry([]string{util.USD, util.EUR, util.CAD, "JPY", "KWD"})
+	require.NoError(t, err)
+
+	provider, err := NewProviderFromConfig(util.Config{}, currencies)
+	require.NoError(t, err)
+	require.Nil(t, provider)
+
+	provider, err = NewProviderFromConfig(util.Config{FXRatesPath: "../fx_rates.json", FXSpreadBps: 50}, currencies)
+	require.NoError(t, err)
+	require.NotNil(t, provider)
+
+	// GBP is enabled but the rates file doesn't quote it
+	currencies, err = util.NewCurrencyRegistry([]string{util.USD, "GBP"})
+	require.NoError(t, err)
+
+	_, err = NewProviderFromConfig(util.Config{FXRatesPath: "../fx_rates.json", FXSpreadBps: 50}, currencies)
+	require.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/chandiniv1/transfers-system/util"
+)
+
+// RateDecimals is the number of decimal places an exchange rate is kept to
+const RateDecimals = 8
+
+// RateScale is the Rate of 1, an exchange between amounts of equal value
+const RateScale Rate = 100_000_000
+
+// maxSpreadBps is the spread that would take the whole amount, in basis points
+const maxSpreadBps = 10_000
+
+// ErrAmountOverflow is returned when a converted amount doesn't fit in an int64
+var ErrAmountOverflow = errors.New("converted amount overflows")
+
+// Rate is the amount of destination currency paid for one unit of the source currency,
+// in units of 10^-RateDecimals.
+type Rate int64
+
+// ParseRate parses a positive decimal rate such as "0.92", rounding it to RateDecimals places
+func ParseRate(s string) (Rate, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("invalid exchange rate %q", s)
+	}
+
+	rate, ok := roundRat(value.Mul(value, new(big.Rat).SetInt64(int64(RateScale))))
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %q: must be positive and representable", s)
+	}
+
+	return Rate(rate), nil
+}
+
+// RateFromDecimal converts the decimal unscaled * 10^exp into a Rate, rounding it to RateDecimals places
+func RateFromDecimal(unscaled *big.Int, exp int32) (Rate, error) {
+	scaled := new(big.Int).Mul(unscaled, big.NewInt(int64(RateScale)))
+
+	value := new(big.Rat)
+	if exp < 0 {
+		value.SetFrac(scaled, pow10(-exp))
+	} else {
+		value.SetInt(scaled.Mul(scaled, pow10(exp)))
+	}
+
+	rate, ok := roundRat(value)
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("invalid exchange rate %se%d", unscaled, exp)
+	}
+
+	return Rate(rate), nil
+}
+
+// Decimal returns the rate as unscaled * 10^exp, the form Postgres numerics are built from
+func (r Rate) Decimal() (unscaled *big.Int, exp int32) {
+	return big.NewInt(int64(r)), -RateDecimals
+}
+
+// String formats the rate with RateDecimals decimal places, like "0.92000000"
+func (r Rate) String() string {
+	return fmt.Sprintf("%d.%0*d", r/RateScale, RateDecimals, r%RateScale)
+}
+
+// Convert returns the destination amount paid for amount of the source currency, rounded down.
+// Both amounts are in minor units, while the rate is quoted per major unit, so the amount is
+// scaled by the difference between the minor units of the two currencies.
+func (r Rate) Convert(amount int64, from, to string) (int64, error) {
+	fromCurrency, ok := util.LookupCurrency(from)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", from)
+	}
+	toCurrency, ok := util.LookupCurrency(to)
+	if !ok {
+		return 0, fmt.Errorf("unknown currency %q", to)
+	}
+
+	converted := new(big.Int).Mul(big.NewInt(amount), big.NewInt(int64(r)))
+	converted.Mul(converted, pow10(int32(toCurrency.MinorUnits)))
+	converted.Quo(converted, new(big.Int).Mul(big.NewInt(int64(RateScale)), pow10(int32(fromCurrency.MinorUnits))))
+
+	if !converted.IsInt64() {
+		return 0, ErrAmountOverflow
+	}
+
+	return converted.Int64(), nil
+}
+
+// Invert returns the rate of the opposite exchange
+func (r Rate) Invert() Rate {
+	inverse, _ := roundRat(big.NewRat(int64(RateScale)*int64(RateScale), int64(r)))
+	return Rate(inverse)
+}
+
+// WithSpread returns the rate lowered by a spread in basis points, the margin kept on a conversion
+func (r Rate) WithSpread(spreadBps int64) Rate {
+	return Rate(int64(r) * (maxSpreadBps - spreadBps) / maxSpreadBps)
+}
+
+// roundRat rounds value half away from zero, reporting false if the result doesn't fit in an int64
+func roundRat(value *big.Rat) (int64, bool) {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if rem.Lsh(rem.Abs(rem), 1).Cmp(value.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(value.Sign())))
+	}
+
+	return quo.Int64(), quo.IsInt64()
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
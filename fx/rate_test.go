@@ -0,0 +1,129 @@
+package fx
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	testCases := []struct {
+		input string
+		rate  Rate
+	}{
+		{"1", RateScale},
+		{"0.92", 92_000_000},
+		{" 1.36 ", 136_000_000},
+		{"0.123456785", 12_345_679},
+		{"0.000000004", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			rate, err := ParseRate(tc.input)
+			if tc.rate == 0 {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.rate, rate)
+		})
+	}
+
+	for _, input := range []string{"", "abc", "-1", "0", "1e20"} {
+		_, err := ParseRate(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestRateString(t *testing.T) {
+	require.Equal(t, "1.00000000", RateScale.String())
+	require.Equal(t, "0.92000000", Rate(92_000_000).String())
+	require.Equal(t, "1.47826087", Rate(147_826_087).String())
+}
+
+func TestRateDecimal(t *testing.T) {
+	rate := Rate(92_000_000)
+
+	unscaled, exp := rate.Decimal()
+	parsed, err := RateFromDecimal(unscaled, exp)
+	require.NoError(t, err)
+	require.Equal(t, rate, parsed)
+
+	// Postgres may drop trailing zeros of a numeric
+	parsed, err = RateFromDecimal(big.NewInt(92), -2)
+	require.NoError(t, err)
+	require.Equal(t, rate, parsed)
+
+	parsed, err = RateFromDecimal(big.NewInt(2), 1)
+	require.NoError(t, err)
+	require.Equal(t, Rate(20)*RateScale, parsed)
+
+	_, err = RateFromDecimal(big.NewInt(0), 0)
+	require.Error(t, err)
+}
+
+func TestRateConvert(t *testing.T) {
+	rate := Rate(92_000_000)
+
+	amount, err := rate.Convert(1000, "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, int64(920), amount)
+
+	// rounded down, in favour of the payer of the conversion
+	amount, err = rate.Convert(33, "USD", "EUR")
+	require.NoError(t, err)
+	require.Equal(t, int64(30), amount)
+
+	_, err = Rate(200*RateScale).Convert(math.MaxInt64/100, "USD", "EUR")
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = rate.Convert(1000, "USD", "XXX")
+	require.Error(t, err)
+}
+
+func TestRateConvertMinorUnits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rate     string
+		amount   int64
+		from     string
+		to       string
+		expected int64
+	}{
+		// 10.00 USD is 1495 JPY, which has no minor unit
+		{"USD to JPY", "149.5", 1000, "USD", "JPY", 1495},
+		// 1000 JPY is 6.70 USD
+		{"JPY to USD", "0.0067", 1000, "JPY", "USD", 670},
+		// 10.00 USD is 3.070 KWD, in fils
+		{"USD to KWD", "0.307", 1000, "USD", "KWD", 3070},
+		// 1.500 KWD is 729.75 JPY, rounded down
+		{"KWD to JPY", "486.5", 1500, "KWD", "JPY", 729},
+		// 1 JPY is 0.002 KWD, rounded down
+		{"JPY to KWD", "0.00205", 1, "JPY", "KWD", 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := ParseRate(tc.rate)
+			require.NoError(t, err)
+
+			amount, err := rate.Convert(tc.amount, tc.from, tc.to)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, amount)
+		})
+	}
+}
+
+func TestRateInvert(t *testing.T) {
+	require.Equal(t, RateScale, RateScale.Invert())
+	require.Equal(t, Rate(50_000_000), Rate(2*RateScale).Invert())
+	require.Equal(t, Rate(108_695_652), Rate(92_000_000).Invert())
+}
+
+func TestRateWithSpread(t *testing.T) {
+	require.Equal(t, Rate(91_540_000), Rate(92_000_000).WithSpread(50))
+	require.Equal(t, Rate(92_000_000), Rate(92_000_000).WithSpread(0))
+}
@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authorizationHeader = "authorization"
+	authorizationBearer = "bearer"
+)
+
+// authorizeUser verifies the bearer token sent in the request metadata and checks
+// that its session has not been revoked, like the HTTP auth middleware does.
+func (server *Server) authorizeUser(ctx context.Context) (*token.Payload, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, unauthenticatedError(fmt.Errorf("missing metadata"))
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return nil, unauthenticatedError(fmt.Errorf("missing authorization header"))
+	}
+
+	fields := strings.Fields(values[0])
+	if len(fields) < 2 {
+		return nil, unauthenticatedError(fmt.Errorf("invalid authorization header format"))
+	}
+
+	authType := strings.ToLower(fields[0])
+	if authType != authorizationBearer {
+		return nil, unauthenticatedError(fmt.Errorf("unsupported authorization type: %s", authType))
+	}
+
+	payload, err := server.tokenMaker.VerifyToken(fields[1])
+	if err != nil {
+		return nil, unauthenticatedError(fmt.Errorf("invalid access token: %s", err))
+	}
+
+	if err := payload.CheckType(token.TokenTypeAccess); err != nil {
+		return nil, unauthenticatedError(fmt.Errorf("invalid access token: %s", err))
+	}
+
+	session, err := server.store.GetSession(ctx, payload.SessionID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, unauthenticatedError(fmt.Errorf("session not found"))
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get session: %s", err)
+	}
+
+	if session.IsBlocked {
+		return nil, unauthenticatedError(fmt.Errorf("session is blocked"))
+	}
+
+	if session.Username != payload.Username {
+		return nil, unauthenticatedError(fmt.Errorf("incorrect session user"))
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,142 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAuthorizeUser(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		buildContext  func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context
+		checkResponse func(t *testing.T, err error)
+	}{
+		{
+			name: "OK",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				return newContextWithBearerToken(t, store, server.tokenMaker, username)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "No Metadata",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				return context.Background()
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Unsupported Authorization Type",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("basic %s", accessToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Expired Token",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, -time.Minute)
+				require.NoError(t, err)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationBearer, accessToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Refresh Token",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				refreshToken, _, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeRefresh, time.Minute)
+				require.NoError(t, err)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(0)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationBearer, refreshToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Session Not Found",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, db.ErrRecordNotFound)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationBearer, accessToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Blocked Session",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				accessToken, payload, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				session := db.Session{ID: payload.SessionID, Username: username, IsBlocked: true}
+				store.EXPECT().GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).Times(1).Return(session, nil)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationBearer, accessToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Session Error",
+			buildContext: func(t *testing.T, server *Server, store *mockdb.MockStore) context.Context {
+				accessToken, _, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+				require.NoError(t, err)
+				store.EXPECT().GetSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, sql.ErrConnDone)
+				md := metadata.Pairs(authorizationHeader, fmt.Sprintf("%s %s", authorizationBearer, accessToken))
+				return metadata.NewIncomingContext(context.Background(), md)
+			},
+			checkResponse: func(t *testing.T, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServer(t, store)
+
+			ctx := tc.buildContext(t, server, store)
+			payload, err := server.authorizeUser(ctx)
+			tc.checkResponse(t, err)
+			if err == nil {
+				require.Equal(t, username, payload.Username)
+			}
+		})
+	}
+}
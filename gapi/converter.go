@@ -0,0 +1,204 @@
+package gapi
+
+import (
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func convertAccount(account db.Account) *pb.Account {
+	availableBalance := account.Balance - account.HeldBalance
+
+	return &pb.Account{
+		AccountId:        account.AccountID,
+		Owner:            account.Owner,
+		Balance:          account.Balance,
+		Currency:         account.Currency,
+		CreatedAt:        timestamppb.New(account.CreatedAt.Time),
+		AvailableBalance: availableBalance,
+
+		FormattedBalance:          convertMoney(util.Money{Amount: account.Balance, Currency: account.Currency}),
+		FormattedAvailableBalance: convertMoney(util.Money{Amount: availableBalance, Currency: account.Currency}),
+	}
+}
+
+func convertMoney(money util.Money) *pb.Money {
+	return &pb.Money{
+		Amount:   money.Decimal(),
+		Currency: money.Currency,
+	}
+}
+
+func convertCurrency(currency util.Currency) *pb.Currency {
+	return &pb.Currency{
+		Code:        currency.Code,
+		NumericCode: currency.NumericCode,
+		Name:        currency.Name,
+		MinorUnits:  int32(currency.MinorUnits),
+	}
+}
+
+func convertTransaction(transaction db.Transaction) *pb.Transaction {
+	pbTransaction := &pb.Transaction{
+		Id:                    transaction.ID,
+		SourceAccountId:       transaction.SourceAccountID,
+		DestinationAccountId:  transaction.DestinationAccountID.Int64,
+		Amount:                transaction.Amount,
+		CreatedAt:             timestamppb.New(transaction.CreatedAt.Time),
+		OriginalTransactionId: transaction.OriginalTransactionID.Int64,
+		Reason:                transaction.Reason.String,
+		ReversedAmount:        transaction.ReversedAmount,
+		DestinationAmount:     transaction.DestinationAmount.Int64,
+	}
+	if rate, ok := transaction.AppliedRate(); ok {
+		pbTransaction.ExchangeRate = rate.String()
+	}
+	return pbTransaction
+}
+
+func convertEntry(entry db.Entry) *pb.Entry {
+	return &pb.Entry{
+		Id:            entry.ID,
+		AccountId:     entry.AccountID,
+		TransactionId: entry.TransactionID.Int64,
+		Amount:        entry.Amount,
+		Balance:       entry.Balance,
+		CreatedAt:     timestamppb.New(entry.CreatedAt.Time),
+	}
+}
+
+func convertTransferResult(result db.TransferTxResult) *pb.CreateTransferResponse {
+	return &pb.CreateTransferResponse{
+		Transaction: convertTransaction(result.Transaction),
+		FromAccount: convertAccount(result.FromAccount),
+		ToAccount:   convertAccount(result.ToAccount),
+		FromEntry:   convertEntry(result.FromEntry),
+		ToEntry:     convertEntry(result.ToEntry),
+	}
+}
+
+func convertTransactionLeg(leg db.TransactionLeg) *pb.TransactionLeg {
+	return &pb.TransactionLeg{
+		Position:  leg.Position,
+		AccountId: leg.AccountID,
+		Amount:    leg.Amount,
+		Memo:      leg.Memo,
+	}
+}
+
+func convertMultiLegTransferResult(result db.MultiLegTransferTxResult) *pb.CreateMultiLegTransferResponse {
+	rsp := &pb.CreateMultiLegTransferResponse{
+		Transaction: convertTransaction(result.Transaction),
+		FromAccount: convertAccount(result.FromAccount),
+	}
+	for _, leg := range result.Legs {
+		rsp.Transaction.Legs = append(rsp.Transaction.Legs, convertTransactionLeg(leg))
+	}
+	for _, entry := range result.Entries {
+		rsp.Entries = append(rsp.Entries, convertEntry(entry))
+	}
+	return rsp
+}
+
+func convertHold(hold db.Hold) *pb.Hold {
+	pbHold := &pb.Hold{
+		Id:             hold.ID,
+		AccountId:      hold.AccountID,
+		ToAccountId:    hold.ToAccountID,
+		Amount:         hold.Amount,
+		Status:         hold.Status,
+		CapturedAmount: hold.CapturedAmount.Int64,
+		TransactionId:  hold.TransactionID.Int64,
+		ExpiresAt:      timestamppb.New(hold.ExpiresAt.Time),
+		CreatedAt:      timestamppb.New(hold.CreatedAt.Time),
+	}
+	if hold.ResolvedAt.Valid {
+		pbHold.ResolvedAt = timestamppb.New(hold.ResolvedAt.Time)
+	}
+	return pbHold
+}
+
+func convertScheduledTransfer(scheduled db.ScheduledTransfer) *pb.ScheduledTransfer {
+	pbScheduled := &pb.ScheduledTransfer{
+		Id:            scheduled.ID,
+		FromAccountId: scheduled.FromAccountID,
+		ToAccountId:   scheduled.ToAccountID,
+		Amount:        scheduled.Amount,
+		ExecuteAt:     timestamppb.New(scheduled.ExecuteAt.Time),
+		Status:        scheduled.Status,
+		TransactionId: scheduled.TransactionID.Int64,
+		FailureReason: scheduled.FailureReason.String,
+		CreatedAt:     timestamppb.New(scheduled.CreatedAt.Time),
+	}
+	if scheduled.ResolvedAt.Valid {
+		pbScheduled.ResolvedAt = timestamppb.New(scheduled.ResolvedAt.Time)
+	}
+	return pbScheduled
+}
+
+func convertStandingOrder(order db.StandingOrder) *pb.StandingOrder {
+	pbOrder := &pb.StandingOrder{
+		Id:                      order.ID,
+		FromAccountId:           order.FromAccountID,
+		ToAccountId:             order.ToAccountID,
+		Amount:                  order.Amount,
+		Schedule:                order.Schedule,
+		StartAt:                 timestamppb.New(order.StartAt.Time),
+		MaxOccurrences:          order.MaxOccurrences.Int32,
+		InsufficientFundsPolicy: order.InsufficientFundsPolicy,
+		Status:                  order.Status,
+		Retries:                 order.Retries,
+		Occurrences:             order.Occurrences,
+		CreatedAt:               timestamppb.New(order.CreatedAt.Time),
+	}
+	if order.EndAt.Valid {
+		pbOrder.EndAt = timestamppb.New(order.EndAt.Time)
+	}
+	if order.Status == db.StandingOrderStatusActive {
+		pbOrder.NextRunAt = timestamppb.New(order.NextRunAt.Time)
+	}
+	if order.RetryAt.Valid {
+		pbOrder.RetryAt = timestamppb.New(order.RetryAt.Time)
+	}
+	if order.EndedAt.Valid {
+		pbOrder.EndedAt = timestamppb.New(order.EndedAt.Time)
+	}
+	return pbOrder
+}
+
+func convertStandingOrderExecution(execution db.StandingOrderExecution) *pb.StandingOrderExecution {
+	return &pb.StandingOrderExecution{
+		Id:              execution.ID,
+		StandingOrderId: execution.StandingOrderID,
+		OccurrenceAt:    timestamppb.New(execution.OccurrenceAt.Time),
+		Status:          execution.Status,
+		TransactionId:   execution.TransactionID.Int64,
+		FailureReason:   execution.FailureReason.String,
+		CreatedAt:       timestamppb.New(execution.CreatedAt.Time),
+	}
+}
+
+func convertTransferBatch(batch db.TransferBatch, items []db.TransferBatchItem) *pb.TransferBatch {
+	pbBatch := &pb.TransferBatch{
+		Id:        batch.ID,
+		Mode:      batch.Mode,
+		Status:    batch.Status,
+		CreatedAt: timestamppb.New(batch.CreatedAt.Time),
+	}
+	if batch.CompletedAt.Valid {
+		pbBatch.CompletedAt = timestamppb.New(batch.CompletedAt.Time)
+	}
+	for _, item := range items {
+		pbBatch.Items = append(pbBatch.Items, &pb.TransferBatchItem{
+			Position:      item.Position,
+			FromAccountId: item.FromAccountID,
+			ToAccountId:   item.ToAccountID,
+			Amount:        item.Amount,
+			Status:        item.Status,
+			TransactionId: item.TransactionID.Int64,
+			Error:         item.Error.String,
+		})
+	}
+	return pbBatch
+}
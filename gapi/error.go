@@ -0,0 +1,129 @@
+package gapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errorDomain                = "transfers-system"
+	reasonInsufficientFunds    = "INSUFFICIENT_FUNDS"
+	reasonIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	reasonTransferBatchFailed  = "TRANSFER_BATCH_FAILED"
+)
+
+// fieldViolation reports why a field was rejected. The reason is the code of err, or invalid
+// for values that couldn't be parsed.
+func fieldViolation(field string, err error) *errdetails.BadRequest_FieldViolation {
+	code := invalidFieldCode
+	var fieldErr *fieldError
+	if errors.As(err, &fieldErr) {
+		code = fieldErr.code
+	}
+
+	return &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: err.Error(),
+		Reason:      code,
+	}
+}
+
+func invalidArgumentError(violations []*errdetails.BadRequest_FieldViolation) error {
+	badRequest := &errdetails.BadRequest{FieldViolations: violations}
+	statusInvalid := status.New(codes.InvalidArgument, "invalid parameters")
+
+	statusDetails, err := statusInvalid.WithDetails(badRequest)
+	if err != nil {
+		return statusInvalid.Err()
+	}
+
+	return statusDetails.Err()
+}
+
+func unauthenticatedError(err error) error {
+	return status.Errorf(codes.Unauthenticated, "unauthorized: %s", err)
+}
+
+// insufficientFundsError reports a rejected transfer with an ErrorInfo detail carrying the amounts,
+// so clients can tell it apart from other failed preconditions.
+func insufficientFundsError(fundsErr *db.InsufficientFundsError) error {
+	return errorWithInfo(codes.FailedPrecondition, fundsErr.Error(), reasonInsufficientFunds, map[string]string{
+		"account_id": strconv.FormatInt(fundsErr.AccountID, 10),
+		"available":  strconv.FormatInt(fundsErr.Available, 10),
+		"requested":  strconv.FormatInt(fundsErr.Requested, 10),
+	})
+}
+
+// errorWithInfo returns a status error with an ErrorInfo detail giving the reason for it
+func errorWithInfo(code codes.Code, msg, reason string, metadata map[string]string) error {
+	info := &errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	}
+	st := status.New(code, msg)
+
+	statusDetails, err := st.WithDetails(info)
+	if err != nil {
+		return st.Err()
+	}
+
+	return statusDetails.Err()
+}
+
+// storeError converts an error returned by the store into a status error with the matching code.
+// Errors the store reports with a sentinel carry an ErrorInfo whose reason is the problem code of the
+// sentinel, so the gateway responds with the same code and status as the HTTP API.
+func storeError(err error, msg string) error {
+	var fundsErr *db.InsufficientFundsError
+	if errors.As(err, &fundsErr) {
+		return insufficientFundsError(fundsErr)
+	}
+
+	problem := db.ErrorProblemCode(err)
+	if problem == "" {
+		return status.Errorf(codes.Internal, "%s: %s", msg, err)
+	}
+
+	// report the sentinel rather than the database error it wraps
+	var code codes.Code
+	var cause error
+	switch {
+	case errors.Is(err, db.ErrRecordNotFound):
+		code, cause = codes.NotFound, db.ErrRecordNotFound
+	case errors.Is(err, db.ErrUniqueViolation):
+		code, cause = codes.AlreadyExists, db.ErrUniqueViolation
+	case errors.Is(err, db.ErrForeignKeyViolation):
+		code, cause = codes.PermissionDenied, db.ErrForeignKeyViolation
+	case errors.Is(err, db.ErrInsufficientFunds):
+		code, cause = codes.FailedPrecondition, db.ErrInsufficientFunds
+	case errors.Is(err, db.ErrCheckViolation):
+		code, cause = codes.InvalidArgument, db.ErrCheckViolation
+	case errors.Is(err, db.ErrAmountOutOfRange):
+		code, cause = codes.OutOfRange, db.ErrAmountOutOfRange
+	case errors.Is(err, db.ErrHoldAmountExceeded):
+		code, cause = codes.InvalidArgument, db.ErrHoldAmountExceeded
+	case errors.Is(err, db.ErrReversalAmountTooSmall):
+		// the store tells how much was asked of what, with no database error to hide
+		code, cause = codes.InvalidArgument, err
+	case errors.Is(err, db.ErrSerializationFailure):
+		code, cause = codes.Aborted, db.ErrSerializationFailure
+	default:
+		// failed preconditions the client can act on, told apart by the reason
+		code, cause = codes.FailedPrecondition, err
+	}
+
+	return errorWithInfo(code, fmt.Sprintf("%s: %s", msg, cause), problemReason(problem), nil)
+}
+
+// problemReason returns the ErrorInfo reason of a problem code, which the gateway turns back into the code
+func problemReason(code string) string {
+	return strings.ToUpper(code)
+}
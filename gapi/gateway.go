@@ -0,0 +1,147 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"strings"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// NewGatewayMux serves the gRPC methods of server as HTTP/JSON, following the
+// google.api.http rules of the protos. Calls are made in-process, without a
+// gRPC connection.
+func NewGatewayMux(ctx context.Context, server *Server) (*runtime.ServeMux, error) {
+	jsonOption := runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	})
+
+	grpcMux := runtime.NewServeMux(
+		jsonOption,
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithErrorHandler(errorHandler),
+	)
+
+	if err := pb.RegisterAccountServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	if err := pb.RegisterCurrencyServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	if err := pb.RegisterTransferServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	if err := pb.RegisterHoldServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	if err := pb.RegisterScheduledTransferServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	if err := pb.RegisterStandingOrderServiceHandlerServer(ctx, grpcMux, server); err != nil {
+		return nil, err
+	}
+
+	return grpcMux, nil
+}
+
+// headerMatcher forwards the Idempotency-Key header as the metadata entry CreateTransfer reads.
+func headerMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "Idempotency-Key" {
+		return idempotencyKeyHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher sends the cache-control metadata ListCurrencies sets as the Cache-Control header.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == cacheControlHeader {
+		return "Cache-Control", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// errorHandler writes errors as RFC 7807 problem details. The HTTP status follows
+// runtime.HTTPStatusFromCode, except for errors whose ErrorInfo reason is a problem code with a fixed
+// status, which are reported with the same status as by the HTTP API.
+func errorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	httpStatus := 0
+	var statusErr *runtime.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		httpStatus, err = statusErr.HTTPStatus, statusErr.Err
+	}
+
+	st := status.Convert(err)
+	if httpStatus == 0 {
+		httpStatus = runtime.HTTPStatusFromCode(st.Code())
+	}
+
+	var (
+		code     string
+		metadata map[string]string
+		fields   []util.FieldError
+	)
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			code, metadata = strings.ToLower(detail.GetReason()), detail.GetMetadata()
+			if problemStatus, ok := util.ProblemStatus(code); ok {
+				httpStatus = problemStatus
+			}
+		case *errdetails.BadRequest:
+			code = util.ProblemValidationFailed
+			for _, violation := range detail.GetFieldViolations() {
+				fields = append(fields, util.FieldError{
+					Field:   violation.GetField(),
+					Code:    violation.GetReason(),
+					Message: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	if code == "" {
+		code = util.ProblemCode(httpStatus)
+	}
+
+	detail := st.Message()
+	if httpStatus >= http.StatusInternalServerError {
+		log.Error().Str("protocol", "http").Str("method", r.Method).Str("path", r.URL.Path).
+			Str("error", st.Message()).Msg("gateway request failed")
+		detail = util.ProblemDetail(util.ProblemInternal)
+	}
+
+	problem := util.NewProblem(httpStatus, code, detail, r.URL.Path)
+	problem.Errors = fields
+	problem.Metadata = metadata
+
+	if st.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	w.Header().Set("Content-Type", util.ProblemContentType)
+	w.WriteHeader(httpStatus)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Error().Err(err).Msg("failed to write problem details")
+	}
+}
@@ -0,0 +1,663 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayMux(t *testing.T) {
+	user := util.RandomOwner()
+	fromAccount := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: util.RandomOwner(), Currency: util.USD, Balance: 500}
+	key := util.RandomString(16)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          string
+		setupRequest  func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore)
+		buildStubs    func(t *testing.T, store *mockdb.MockStore)
+		checkResponse func(t *testing.T, rec *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Get Account",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", fromAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var account map[string]interface{}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &account))
+				require.Equal(t, "1", account["account_id"])
+				require.Equal(t, user, account["owner"])
+				require.Equal(t, util.USD, account["currency"])
+				require.Equal(t, "1000", account["available_balance"])
+			},
+		},
+		{
+			name:   "List Currencies",
+			method: http.MethodGet,
+			url:    "/currencies",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+				require.Equal(t, currenciesCacheControl, rec.Header().Get("Cache-Control"))
+
+				var rsp struct {
+					Currencies []util.Currency `json:"currencies"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+				require.Contains(t, rsp.Currencies, util.Currency{Code: "JPY", NumericCode: "392", Name: "Yen", MinorUnits: 0})
+			},
+		},
+		{
+			name:   "Cancel Standing Order Not Active",
+			method: http.MethodPost,
+			url:    "/standing-orders/7/cancel",
+			body:   `{}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				order := db.StandingOrder{ID: 7, FromAccountID: fromAccount.AccountID, Status: db.StandingOrderStatusCanceled}
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{}, db.ErrStandingOrderNotActive)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemStandingOrderNotActive, problem.Code)
+			},
+		},
+		{
+			name:   "Account Not Found",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", fromAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemNotFound, problem.Code)
+				require.NotContains(t, problem.Detail, "no rows")
+			},
+		},
+		{
+			name:   "Create Duplicate Account",
+			method: http.MethodPost,
+			url:    "/accounts",
+			body:   `{"account_id": "3", "currency": "USD", "balance": "0"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemAlreadyExists, problem.Code)
+			},
+		},
+		{
+			name:   "No Authorization",
+			method: http.MethodGet,
+			url:    "/accounts?page_size=5",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, rec.Code)
+			},
+		},
+		{
+			name:   "List Accounts Query Parameters",
+			method: http.MethodGet,
+			url:    "/accounts?currency=USD&min_balance=100&page_size=5",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Owner:      user,
+					Currency:   pgtype.Text{String: util.USD, Valid: true},
+					MinBalance: pgtype.Int8{Int64: 100, Valid: true},
+					PageLimit:  6,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{fromAccount}, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var rsp struct {
+					Accounts   []map[string]interface{} `json:"accounts"`
+					NextCursor string                   `json:"next_cursor"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Accounts, 1)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:   "Create Transfer With Idempotency Key",
+			method: http.MethodPost,
+			url:    "/transactions",
+			body:   `{"from_account_id": 1, "to_account_id": 2, "amount": 10, "currency": "USD"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+				request.Header.Set("Idempotency-Key", key)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(db.IdempotencyKey{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.Equal(t, int64(10), arg.Amount)
+						require.NotNil(t, arg.Idempotency)
+						require.Equal(t, key, arg.Idempotency.Key)
+						return db.TransferTxResult{
+							Transaction: db.Transaction{ID: 5, SourceAccountID: 1, DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true}, Amount: 10},
+							FromAccount: fromAccount,
+							ToAccount:   toAccount,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var rsp map[string]map[string]interface{}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+				require.Equal(t, "5", rsp["transaction"]["id"])
+			},
+		},
+		{
+			name:   "Idempotency Key Reused",
+			method: http.MethodPost,
+			url:    "/transactions",
+			body:   `{"from_account_id": 1, "to_account_id": 2, "amount": 20, "currency": "USD"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+				request.Header.Set("Idempotency-Key", key)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				stored := db.IdempotencyKey{Username: user, Key: key, RequestHash: "other"}
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(stored, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemIdempotencyKeyReused, problem.Code)
+			},
+		},
+		{
+			name:   "Insufficient Funds",
+			method: http.MethodPost,
+			url:    "/transactions",
+			body:   `{"from_account_id": 1, "to_account_id": 2, "amount": 5000, "currency": "USD"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				fundsErr := &db.InsufficientFundsError{AccountID: fromAccount.AccountID, Available: fromAccount.Balance, Requested: 5000}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, fundsErr)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+				require.Equal(t, util.ProblemContentType, rec.Header().Get("Content-Type"))
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemInsufficientFunds, problem.Code)
+				require.Equal(t, "/problems/insufficient_funds", problem.Type)
+				require.Equal(t, http.StatusUnprocessableEntity, problem.Status)
+				require.Equal(t, "/transactions", problem.Instance)
+				require.Equal(t, "1000", problem.Metadata["available"])
+				require.Equal(t, "5000", problem.Metadata["requested"])
+			},
+		},
+		{
+			name:   "Capture Inactive Hold",
+			method: http.MethodPost,
+			url:    "/holds/7/capture",
+			body:   `{}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				hold := db.Hold{ID: 7, AccountID: fromAccount.AccountID, ToAccountID: toAccount.AccountID, Amount: 100, Status: db.HoldStatusVoided}
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldNotActive)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemHoldNotActive, problem.Code)
+			},
+		},
+		{
+			name:   "Cancel Executed Scheduled Transfer",
+			method: http.MethodPost,
+			url:    "/scheduled-transfers/7/cancel",
+			body:   `{}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				scheduled := db.ScheduledTransfer{ID: 7, FromAccountID: fromAccount.AccountID, ToAccountID: toAccount.AccountID, Amount: 100, Status: db.ScheduledTransferStatusExecuted}
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(db.ScheduledTransfer{}, db.ErrScheduledTransferNotPending)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemScheduledTransferNotPending, problem.Code)
+			},
+		},
+		{
+			name:   "Cancel Completed Standing Order",
+			method: http.MethodPost,
+			url:    "/standing-orders/7/cancel",
+			body:   `{}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				order := db.StandingOrder{ID: 7, FromAccountID: fromAccount.AccountID, ToAccountID: toAccount.AccountID, Amount: 100, Status: db.StandingOrderStatusCompleted}
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{}, db.ErrStandingOrderNotActive)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemStandingOrderNotActive, problem.Code)
+			},
+		},
+		{
+			name:   "Transfer Batch Stopped",
+			method: http.MethodPost,
+			url:    "/transactions/batch",
+			body:   fmt.Sprintf(`{"mode": "best_effort", "transfers": [{"from_account_id": %d, "to_account_id": %d, "amount": 100, "currency": "USD"}]}`, fromAccount.AccountID, toAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				result := db.TransferBatchTxResult{
+					Batch: db.TransferBatch{ID: 7, Owner: user, Mode: db.TransferBatchModeBestEffort, Status: db.TransferBatchStatusFailed},
+				}
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{fromAccount, toAccount}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(1).Return(result, errors.New("connection reset"))
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemTransferBatchFailed, problem.Code)
+				require.Equal(t, "7", problem.Metadata["batch_id"])
+				require.NotContains(t, problem.Detail, "connection reset")
+			},
+		},
+		{
+			name:   "Get Transfer Batch",
+			method: http.MethodGet,
+			url:    "/transactions/batch/7",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				batch := db.TransferBatch{ID: 7, Owner: user, Mode: db.TransferBatchModeBestEffort, Status: db.TransferBatchStatusPartial}
+				items := []db.TransferBatchItem{
+					{BatchID: batch.ID, Position: 0, FromAccountID: fromAccount.AccountID, ToAccountID: toAccount.AccountID, Amount: 100, Status: db.TransferBatchItemStatusSucceeded, TransactionID: pgtype.Int8{Int64: 3, Valid: true}},
+					{BatchID: batch.ID, Position: 1, FromAccountID: fromAccount.AccountID, ToAccountID: toAccount.AccountID, Amount: 5000, Status: db.TransferBatchItemStatusFailed, Error: pgtype.Text{String: "insufficient funds", Valid: true}},
+				}
+				store.EXPECT().GetTransferBatch(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(batch, nil)
+				store.EXPECT().ListTransferBatchItems(gomock.Any(), gomock.Eq(batch.ID)).Times(1).Return(items, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var batch struct {
+					ID     string `json:"id"`
+					Status string `json:"status"`
+					Items  []struct {
+						Status        string `json:"status"`
+						TransactionID string `json:"transaction_id"`
+						Error         string `json:"error"`
+					} `json:"items"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &batch))
+				require.Equal(t, "7", batch.ID)
+				require.Equal(t, db.TransferBatchStatusPartial, batch.Status)
+				require.Len(t, batch.Items, 2)
+				require.Equal(t, "3", batch.Items[0].TransactionID)
+				require.Equal(t, "insufficient funds", batch.Items[1].Error)
+			},
+		},
+		{
+			name:   "Get Multi-Leg Transaction",
+			method: http.MethodGet,
+			url:    "/transactions/8",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				transaction := db.Transaction{ID: 8, SourceAccountID: fromAccount.AccountID, Amount: 10}
+				legs := []db.TransactionLeg{
+					{TransactionID: transaction.ID, Position: 0, AccountID: fromAccount.AccountID, Amount: -10},
+					{TransactionID: transaction.ID, Position: 1, AccountID: toAccount.AccountID, Amount: 10, Memo: "rent"},
+				}
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(transaction, nil)
+				store.EXPECT().ListTransactionLegs(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(legs, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var transaction struct {
+					ID   string `json:"id"`
+					Legs []struct {
+						AccountID string `json:"account_id"`
+						Amount    string `json:"amount"`
+						Memo      string `json:"memo"`
+					} `json:"legs"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &transaction))
+				require.Equal(t, "8", transaction.ID)
+				require.Len(t, transaction.Legs, 2)
+				require.Equal(t, "-10", transaction.Legs[0].Amount)
+				require.Equal(t, "2", transaction.Legs[1].AccountID)
+				require.Equal(t, "rent", transaction.Legs[1].Memo)
+			},
+		},
+		{
+			name:   "List Account Transactions",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d/transactions?direction=outgoing&min_amount=5&created_from=2024-05-01T00:00:00Z&page_size=1", fromAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				arg := db.ListAccountTransactionsParams{
+					AccountID:       fromAccount.AccountID,
+					IncludeOutgoing: true,
+					MinAmount:       pgtype.Int8{Int64: 5, Valid: true},
+					CreatedFrom:     pgtype.Timestamptz{Time: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					PageLimit:       2,
+				}
+				transactions := []db.ListAccountTransactionsRow{
+					{
+						Transaction:   db.Transaction{ID: 4, SourceAccountID: fromAccount.AccountID, DestinationAccountID: pgtype.Int8{Int64: toAccount.AccountID, Valid: true}, Amount: 20},
+						AccountAmount: 20,
+					},
+					{
+						Transaction:   db.Transaction{ID: 3, SourceAccountID: fromAccount.AccountID, DestinationAccountID: pgtype.Int8{Int64: toAccount.AccountID, Valid: true}, Amount: 10},
+						AccountAmount: 10,
+					},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transactions, nil)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, rec.Code)
+
+				var rsp struct {
+					Transactions []struct {
+						ID            string `json:"id"`
+						AccountAmount string `json:"account_amount"`
+					} `json:"transactions"`
+					NextCursor string `json:"next_cursor"`
+				}
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Transactions, 1)
+				require.Equal(t, "4", rsp.Transactions[0].ID)
+				require.Equal(t, "20", rsp.Transactions[0].AccountAmount)
+				require.NotEmpty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:   "List Account Transactions Invalid Direction",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d/transactions?direction=sideways", fromAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemValidationFailed, problem.Code)
+				require.Equal(t, "direction", problem.Errors[0].Field)
+			},
+		},
+		{
+			name:   "Reverse Over Refund",
+			method: http.MethodPost,
+			url:    "/transactions/5/reverse",
+			body:   `{"amount": 100, "reason": "refund"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				transaction := db.Transaction{ID: 5, SourceAccountID: toAccount.AccountID, DestinationAccountID: pgtype.Int8{Int64: fromAccount.AccountID, Valid: true}, Amount: 60}
+				arg := db.ReverseTransferTxParams{TransactionID: 5, Amount: 100, Reason: "refund"}
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(transaction, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrReversalAmountExceeded)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemReversalAmountExceeded, problem.Code)
+			},
+		},
+		{
+			name:   "Invalid Arguments",
+			method: http.MethodPost,
+			url:    "/transactions",
+			body:   `{"from_account_id": 1, "to_account_id": 2, "amount": 0, "currency": "XYZ"}`,
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemValidationFailed, problem.Code)
+				require.Equal(t, []util.FieldError{
+					{Field: "amount", Code: "gt", Message: "must be greater than 0"},
+					{Field: "currency", Code: "currency", Message: `unsupported currency "XYZ"`},
+				}, problem.Errors)
+			},
+		},
+		{
+			name:   "Internal Error",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/accounts/%d", fromAccount.AccountID),
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+				addAuthorization(t, request, server, store, user)
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, errors.New("connection refused"))
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, rec.Code)
+
+				var problem util.Problem
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+				require.Equal(t, util.ProblemInternal, problem.Code)
+				require.NotContains(t, problem.Detail, "connection refused")
+			},
+		},
+		{
+			name:   "Unknown Route",
+			method: http.MethodGet,
+			url:    "/unknown",
+			setupRequest: func(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore) {
+			},
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, rec.Code)
+				require.Equal(t, util.ProblemContentType, rec.Header().Get("Content-Type"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(t, store)
+
+			server := newTestServer(t, store)
+			grpcMux, err := NewGatewayMux(context.Background(), server)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			tc.setupRequest(t, request, server, store)
+
+			rec := httptest.NewRecorder()
+			grpcMux.ServeHTTP(rec, request)
+			tc.checkResponse(t, rec)
+		})
+	}
+}
+
+func TestGatewayIdempotentReplay(t *testing.T) {
+	user := util.RandomOwner()
+	fromAccount := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: util.RandomOwner(), Currency: util.USD, Balance: 500}
+	key := util.RandomString(16)
+	body := `{"from_account_id": 1, "to_account_id": 2, "amount": 10, "currency": "USD"}`
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+	grpcMux, err := NewGatewayMux(context.Background(), server)
+	require.NoError(t, err)
+
+	send := func() *httptest.ResponseRecorder {
+		request, err := http.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		addAuthorization(t, request, server, store, user)
+		request.Header.Set("Idempotency-Key", key)
+
+		rec := httptest.NewRecorder()
+		grpcMux.ServeHTTP(rec, request)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	var stored db.IdempotencyKey
+	gomock.InOrder(
+		store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(db.IdempotencyKey{}, db.ErrRecordNotFound),
+		store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(
+			func(_ context.Context, _ db.GetIdempotencyKeyParams) (db.IdempotencyKey, error) {
+				return stored, nil
+			}),
+	)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			result := db.TransferTxResult{
+				Transaction: db.Transaction{ID: 5, SourceAccountID: 1, DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true}, Amount: 10},
+				FromAccount: fromAccount,
+				ToAccount:   toAccount,
+			}
+			responseBody, err := arg.Idempotency.RenderResponse(result)
+			require.NoError(t, err)
+			stored = db.IdempotencyKey{Username: user, Key: key, RequestHash: arg.Idempotency.RequestHash, ResponseBody: responseBody}
+			return result, nil
+		})
+
+	first := send()
+	replay := send()
+	require.Equal(t, first.Body.String(), replay.Body.String())
+}
+
+// addAuthorization sets a bearer token for username on the request and makes its session active.
+func addAuthorization(t *testing.T, request *http.Request, server *Server, store *mockdb.MockStore, username string) {
+	accessToken, payload, err := server.tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+		AnyTimes().
+		Return(db.Session{ID: payload.SessionID, Username: username}, nil)
+
+	request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestServer(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		IdempotencyKeyTTL:   time.Hour,
+		HoldDuration:        time.Hour,
+		Currencies:          []string{util.USD, util.EUR, util.CAD, "JPY", "KWD"},
+		FXRatesPath:         "../fx_rates.json",
+		FXSpreadBps:         50,
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+
+	return server
+}
+
+// newContextWithBearerToken returns an incoming context carrying an access token for username
+// and makes the store report its session as active.
+func newContextWithBearerToken(t *testing.T, store *mockdb.MockStore, tokenMaker token.Maker, username string) context.Context {
+	accessToken, payload, err := tokenMaker.CreateToken(username, uuid.Nil, token.TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:        payload.SessionID,
+		Username:  payload.Username,
+		ExpiresAt: pgtype.Timestamptz{Time: payload.ExpiredAt, Valid: true},
+	}
+	store.EXPECT().
+		GetSession(gomock.Any(), gomock.Eq(payload.SessionID)).
+		AnyTimes().
+		Return(session, nil)
+
+	md := metadata.MD{
+		authorizationHeader: []string{fmt.Sprintf("%s %s", authorizationBearer, accessToken)},
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func requireStatusCode(t *testing.T, err error, code codes.Code) {
+	require.Error(t, err)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, code, st.Code())
+}
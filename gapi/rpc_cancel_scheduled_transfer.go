@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+)
+
+func (server *Server) CancelScheduledTransfer(ctx context.Context, req *pb.CancelScheduledTransferRequest) (*pb.CancelScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateScheduledTransferID(req.GetScheduledTransferId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if _, err := server.ownScheduledTransfer(ctx, req.GetScheduledTransferId(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := server.store.CancelScheduledTransferTx(ctx, req.GetScheduledTransferId())
+	if err != nil {
+		return nil, storeError(err, "failed to cancel scheduled transfer")
+	}
+
+	rsp := &pb.CancelScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
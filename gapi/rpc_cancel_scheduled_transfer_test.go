@@ -0,0 +1,123 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelScheduledTransferAPI(t *testing.T) {
+	user := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	scheduled := db.ScheduledTransfer{ID: 7, FromAccountID: account.AccountID, ToAccountID: 2, Amount: 300, Status: db.ScheduledTransferStatusPending}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CancelScheduledTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      &pb.CancelScheduledTransferRequest{ScheduledTransferId: scheduled.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				canceled := scheduled
+				canceled.Status = db.ScheduledTransferStatusCanceled
+
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(canceled, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.ScheduledTransferStatusCanceled, res.GetScheduledTransfer().GetStatus())
+			},
+		},
+		{
+			name:     "Not Pending",
+			req:      &pb.CancelScheduledTransferRequest{ScheduledTransferId: scheduled.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, db.ErrScheduledTransferNotPending)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemScheduledTransferNotPending), info.GetReason())
+			},
+		},
+		{
+			name:     "Not Found",
+			req:      &pb.CancelScheduledTransferRequest{ScheduledTransferId: scheduled.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(db.ScheduledTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.CancelScheduledTransferRequest{ScheduledTransferId: scheduled.ID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetScheduledTransfer(gomock.Any(), gomock.Eq(scheduled.ID)).Times(1).Return(scheduled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			req:      &pb.CancelScheduledTransferRequest{ScheduledTransferId: 0},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CancelScheduledTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CancelScheduledTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
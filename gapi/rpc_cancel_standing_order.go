@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+)
+
+func (server *Server) CancelStandingOrder(ctx context.Context, req *pb.CancelStandingOrderRequest) (*pb.CancelStandingOrderResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateStandingOrderID(req.GetStandingOrderId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if _, err := server.ownStandingOrder(ctx, req.GetStandingOrderId(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	order, err := server.store.CancelStandingOrderTx(ctx, req.GetStandingOrderId())
+	if err != nil {
+		return nil, storeError(err, "failed to cancel standing order")
+	}
+
+	rsp := &pb.CancelStandingOrderResponse{
+		StandingOrder: convertStandingOrder(order),
+	}
+	return rsp, nil
+}
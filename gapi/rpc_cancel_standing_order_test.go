@@ -0,0 +1,142 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelStandingOrderAPI(t *testing.T) {
+	user := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	order := db.StandingOrder{
+		ID:                      7,
+		FromAccountID:           account.AccountID,
+		ToAccountID:             2,
+		Amount:                  300,
+		Schedule:                util.ScheduleMonthly,
+		InsufficientFundsPolicy: db.InsufficientFundsPolicySkip,
+		Status:                  db.StandingOrderStatusActive,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CancelStandingOrderRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CancelStandingOrderResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      &pb.CancelStandingOrderRequest{StandingOrderId: order.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				canceled := order
+				canceled.Status = db.StandingOrderStatusCanceled
+
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(canceled, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.StandingOrderStatusCanceled, res.GetStandingOrder().GetStatus())
+			},
+		},
+		{
+			name:     "Not Active",
+			req:      &pb.CancelStandingOrderRequest{StandingOrderId: order.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Any()).Times(1).Return(db.StandingOrder{}, db.ErrStandingOrderNotActive)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemStandingOrderNotActive), info.GetReason())
+			},
+		},
+		{
+			name:     "Not Found",
+			req:      &pb.CancelStandingOrderRequest{StandingOrderId: order.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{}, db.ErrRecordNotFound)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.CancelStandingOrderRequest{StandingOrderId: order.ID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "No Authorization",
+			req:  &pb.CancelStandingOrderRequest{StandingOrderId: order.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			req:      &pb.CancelStandingOrderRequest{StandingOrderId: 0},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CancelStandingOrderTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CancelStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CancelStandingOrder(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
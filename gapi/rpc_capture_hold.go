@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) CaptureHold(ctx context.Context, req *pb.CaptureHoldRequest) (*pb.CaptureHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCaptureHoldRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if _, err := server.ownHold(ctx, req.GetHoldId(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.CaptureHoldTx(ctx, db.CaptureHoldTxParams{
+		HoldID: req.GetHoldId(),
+		Amount: req.GetAmount(),
+	})
+	if err != nil {
+		return nil, storeError(err, "failed to capture hold")
+	}
+
+	rsp := &pb.CaptureHoldResponse{
+		Hold:     convertHold(result.Hold),
+		Transfer: convertTransferResult(result.Transfer),
+	}
+	return rsp, nil
+}
+
+func validateCaptureHoldRequest(req *pb.CaptureHoldRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateHoldID(req.GetHoldId())
+
+	if req.GetAmount() < 0 {
+		violations = append(violations, fieldViolation("amount", invalidField("min", "must not be negative")))
+	}
+
+	return violations
+}
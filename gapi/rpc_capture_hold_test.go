@@ -0,0 +1,158 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCaptureHoldAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000, HeldBalance: 300}
+	hold := db.Hold{ID: 7, AccountID: account.AccountID, ToAccountID: 2, Amount: 300, Status: db.HoldStatusActive}
+
+	result := db.CaptureHoldTxResult{
+		Hold: db.Hold{
+			ID:             hold.ID,
+			AccountID:      hold.AccountID,
+			ToAccountID:    hold.ToAccountID,
+			Amount:         hold.Amount,
+			Status:         db.HoldStatusCaptured,
+			CapturedAmount: pgtype.Int8{Int64: 200, Valid: true},
+			TransactionID:  pgtype.Int8{Int64: 5, Valid: true},
+		},
+		Transfer: db.TransferTxResult{
+			Transaction: db.Transaction{ID: 5, SourceAccountID: 1, DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true}, Amount: 200},
+			FromAccount: db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 800},
+			ToAccount:   db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 200},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CaptureHoldRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CaptureHoldResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID, Amount: 200},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CaptureHoldTxParams{HoldID: hold.ID, Amount: 200}
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.HoldStatusCaptured, res.GetHold().GetStatus())
+				require.Equal(t, int64(200), res.GetHold().GetCapturedAmount())
+				require.Equal(t, int64(5), res.GetHold().GetTransactionId())
+				require.Equal(t, int64(800), res.GetTransfer().GetFromAccount().GetAvailableBalance())
+				require.Equal(t, int64(200), res.GetTransfer().GetTransaction().GetAmount())
+			},
+		},
+		{
+			name:     "Not Active",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldNotActive)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemHoldNotActive), info.GetReason())
+			},
+		},
+		{
+			name:     "Amount Exceeded",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID, Amount: 301},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.CaptureHoldTxResult{}, db.ErrHoldAmountExceeded)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Not Found",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(db.Hold{}, db.ErrRecordNotFound)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Negative Amount",
+			req:      &pb.CaptureHoldRequest{HoldId: hold.ID, Amount: -1},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CaptureHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CaptureHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CaptureHold(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+func (server *Server) CreateAccount(ctx context.Context, req *pb.CreateAccountRequest) (*pb.CreateAccountResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateAccountRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	arg := db.CreateAccountTxParams{
+		CreateAccountParams: db.CreateAccountParams{
+			AccountID: req.GetAccountId(),
+			Owner:     authPayload.Username,
+			Balance:   req.GetBalance(),
+			Currency:  req.GetCurrency(),
+		},
+		OnePerCurrency: server.config.OneAccountPerCurrency,
+	}
+
+	account, err := server.store.CreateAccountTx(ctx, arg)
+	if err != nil {
+		return nil, storeError(err, "failed to create account")
+	}
+
+	rsp := &pb.CreateAccountResponse{
+		Account: convertAccount(account),
+	}
+	return rsp, nil
+}
+
+func validateCreateAccountRequest(req *pb.CreateAccountRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := validateCurrency(currencies, req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	if req.GetBalance() < 0 {
+		violations = append(violations, fieldViolation("balance", invalidField("min", "must not be negative")))
+	}
+
+	return violations
+}
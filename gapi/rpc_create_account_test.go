@@ -0,0 +1,125 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateAccountAPI(t *testing.T) {
+	user := util.RandomOwner()
+	account := randomAccount(user)
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateAccountRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateAccountResponse, err error)
+	}{
+		{
+			name: "OK",
+			req: &pb.CreateAccountRequest{
+				AccountId: account.AccountID,
+				Currency:  account.Currency,
+				Balance:   account.Balance,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAccountTxParams{
+					CreateAccountParams: db.CreateAccountParams{
+						AccountID: account.AccountID,
+						Owner:     user,
+						Balance:   account.Balance,
+						Currency:  account.Currency,
+					},
+				}
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, account.AccountID, res.GetAccount().GetAccountId())
+				require.Equal(t, user, res.GetAccount().GetOwner())
+			},
+		},
+		{
+			name: "Duplicate Currency",
+			req: &pb.CreateAccountRequest{
+				AccountId: account.AccountID,
+				Currency:  account.Currency,
+				Balance:   account.Balance,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Account{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, err, codes.AlreadyExists)
+			},
+		},
+		{
+			name: "Internal Error",
+			req: &pb.CreateAccountRequest{
+				AccountId: account.AccountID,
+				Currency:  account.Currency,
+				Balance:   account.Balance,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name: "Invalid Arguments",
+			req: &pb.CreateAccountRequest{
+				AccountId: 0,
+				Currency:  "XYZ",
+				Balance:   -1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateAccountResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, store, server.tokenMaker, user)
+
+			res, err := server.CreateAccount(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestCreateAccountNoAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().CreateAccountTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	_, err := server.CreateAccount(context.Background(), &pb.CreateAccountRequest{})
+	requireStatusCode(t, err, codes.Unauthenticated)
+}
@@ -0,0 +1,85 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateHold(ctx context.Context, req *pb.CreateHoldRequest) (*pb.CreateHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateHoldRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	amount := requestAmount(req)
+
+	account, err := server.validAccount(ctx, req.GetAccountId(), amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	if _, err := server.validAccount(ctx, req.GetToAccountId(), amount.Currency); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(server.config.HoldDuration)
+	if req.ExpiresAt != nil {
+		expiresAt = req.GetExpiresAt().AsTime()
+	}
+
+	result, err := server.store.CreateHoldTx(ctx, db.CreateHoldTxParams{
+		AccountID:   req.GetAccountId(),
+		ToAccountID: req.GetToAccountId(),
+		Amount:      amount.Amount,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return nil, storeError(err, "failed to create hold")
+	}
+
+	rsp := &pb.CreateHoldResponse{
+		Hold:    convertHold(result.Hold),
+		Account: convertAccount(result.Account),
+	}
+	return rsp, nil
+}
+
+func validateCreateHoldRequest(req *pb.CreateHoldRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	if err := validateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if req.GetAccountId() != 0 && req.GetAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", invalidField("nefield", "must differ from account_id")))
+	}
+
+	violations = append(violations, validateAmount(req, currencies, requestField)...)
+
+	if req.ExpiresAt != nil {
+		if err := req.GetExpiresAt().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("expires_at", err))
+		} else if !req.GetExpiresAt().AsTime().After(time.Now()) {
+			violations = append(violations, fieldViolation("expires_at", invalidField("gt", "must be in the future")))
+		}
+	}
+
+	return violations
+}
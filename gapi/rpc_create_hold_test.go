@@ -0,0 +1,267 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateHoldAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	cadAccount := db.Account{AccountID: 3, Owner: user2, Currency: util.CAD, Balance: 500}
+	amount := int64(300)
+
+	req := &pb.CreateHoldRequest{
+		AccountId:   account.AccountID,
+		ToAccountId: toAccount.AccountID,
+		Amount:      amount,
+		Currency:    util.USD,
+	}
+
+	result := db.HoldTxResult{
+		Hold: db.Hold{
+			ID:          1,
+			AccountID:   account.AccountID,
+			ToAccountID: toAccount.AccountID,
+			Amount:      amount,
+			Status:      db.HoldStatusActive,
+		},
+		Account: db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000, HeldBalance: amount},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateHoldRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateHoldResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateHoldTxParams) (db.HoldTxResult, error) {
+						require.Equal(t, account.AccountID, arg.AccountID)
+						require.Equal(t, toAccount.AccountID, arg.ToAccountID)
+						require.Equal(t, amount, arg.Amount)
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Minute)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.HoldStatusActive, res.GetHold().GetStatus())
+				require.Equal(t, amount, res.GetHold().GetAmount())
+				require.Nil(t, res.GetHold().GetResolvedAt())
+				require.Equal(t, int64(1000), res.GetAccount().GetBalance())
+				require.Equal(t, int64(700), res.GetAccount().GetAvailableBalance())
+			},
+		},
+		{
+			name: "Explicit Expiry",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: toAccount.AccountID,
+				Amount:      amount,
+				Currency:    util.USD,
+				ExpiresAt:   timestamppb.New(time.Now().Add(48 * time.Hour)),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateHoldTxParams) (db.HoldTxResult, error) {
+						require.WithinDuration(t, time.Now().Add(48*time.Hour), arg.ExpiresAt, time.Minute)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Insufficient Funds",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				fundsErr := &db.InsufficientFundsError{AccountID: account.AccountID, Available: 100, Requested: amount}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.HoldTxResult{}, fundsErr)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "OK With Money",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: toAccount.AccountID,
+				Money:       &pb.Money{Amount: "3.00", Currency: util.USD},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateHoldTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateHoldTxParams) (db.HoldTxResult, error) {
+						require.Equal(t, amount, arg.Amount)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, amount, res.GetHold().GetAmount())
+			},
+		},
+		{
+			name: "Money Along With Amount",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: toAccount.AccountID,
+				Amount:      amount,
+				Money:       &pb.Money{Amount: "3.00", Currency: util.USD},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Currency Mismatch",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: cadAccount.AccountID,
+				Amount:      amount,
+				Currency:    util.USD,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cadAccount.AccountID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Past Expiry",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: toAccount.AccountID,
+				Amount:      amount,
+				Currency:    util.USD,
+				ExpiresAt:   timestamppb.New(time.Now().Add(-time.Minute)),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Same Account",
+			req: &pb.CreateHoldRequest{
+				AccountId:   account.AccountID,
+				ToAccountId: account.AccountID,
+				Amount:      amount,
+				Currency:    util.USD,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.HoldTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateHoldResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateHold(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
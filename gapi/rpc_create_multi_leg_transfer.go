@@ -0,0 +1,168 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// maxMultiLegCredits bounds the number of accounts a multi-leg transfer locks
+	maxMultiLegCredits = 20
+	maxMemoLength      = 255
+)
+
+func (server *Server) CreateMultiLegTransfer(ctx context.Context, req *pb.CreateMultiLegTransferRequest) (*pb.CreateMultiLegTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateMultiLegTransferRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	accountIDs := []int64{req.GetFromAccountId()}
+	for _, credit := range req.GetCredits() {
+		accountIDs = append(accountIDs, credit.GetAccountId())
+	}
+
+	found, err := server.store.ListAccountsByID(ctx, accountIDs)
+	if err != nil {
+		return nil, storeError(err, "failed to get accounts")
+	}
+
+	accounts := make(map[int64]db.Account, len(found))
+	for _, account := range found {
+		accounts[account.AccountID] = account
+	}
+
+	for _, accountID := range accountIDs {
+		account, ok := accounts[accountID]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "failed to get account %d: %s", accountID, db.ErrRecordNotFound)
+		}
+
+		if account.Currency != req.GetCurrency() {
+			return nil, status.Errorf(codes.InvalidArgument, "account %d currency mismatch", accountID)
+		}
+	}
+
+	if accounts[req.GetFromAccountId()].Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	arg := db.MultiLegTransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+	}
+	for _, credit := range req.GetCredits() {
+		arg.Credits = append(arg.Credits, db.MultiLegCredit{
+			AccountID: credit.GetAccountId(),
+			Amount:    creditAmount(credit, req.GetCurrency()),
+			Memo:      credit.GetMemo(),
+		})
+	}
+
+	result, err := server.store.MultiLegTransferTx(ctx, arg)
+	if err != nil {
+		return nil, storeError(err, "failed to transfer")
+	}
+
+	return convertMultiLegTransferResult(result), nil
+}
+
+func validateCreateMultiLegTransferRequest(req *pb.CreateMultiLegTransferRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := validateCurrency(currencies, req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation("currency", err))
+	}
+
+	switch n := len(req.GetCredits()); {
+	case n == 0:
+		violations = append(violations, fieldViolation("credits", invalidField("required", "must not be empty")))
+	case n > maxMultiLegCredits:
+		violations = append(violations, fieldViolation("credits", invalidField("max", "must have at most %d credits", maxMultiLegCredits)))
+	}
+
+	// the debit must fit in an amount, like every credit
+	total := util.Money{Currency: req.GetCurrency()}
+	var err error
+	credited := make(map[int64]bool, len(req.GetCredits()))
+	for i, credit := range req.GetCredits() {
+		field := func(name string) string {
+			return fmt.Sprintf("credits[%d].%s", i, name)
+		}
+
+		if err := validateID(credit.GetAccountId()); err != nil {
+			violations = append(violations, fieldViolation(field("account_id"), err))
+		} else if credit.GetAccountId() == req.GetFromAccountId() {
+			violations = append(violations, fieldViolation(field("account_id"), invalidField("nefield", "must differ from from_account_id")))
+		} else if credited[credit.GetAccountId()] {
+			violations = append(violations, fieldViolation(field("account_id"), invalidField("unique", "must not be credited twice")))
+		}
+		credited[credit.GetAccountId()] = true
+
+		amount, amountViolations := validateCreditAmount(credit, req.GetCurrency(), field)
+		if amountViolations != nil {
+			violations = append(violations, amountViolations...)
+		} else if total, err = total.Add(util.Money{Amount: amount, Currency: total.Currency}); err != nil {
+			violations = append(violations, fieldViolation(field("amount"), invalidField("max", "brings the total over the maximum amount: %v", err)))
+			break
+		}
+
+		if len(credit.GetMemo()) > maxMemoLength {
+			violations = append(violations, fieldViolation(field("memo"), invalidField("max", "must be at most %d characters", maxMemoLength)))
+		}
+	}
+
+	return violations
+}
+
+// validateCreditAmount checks the amount of a credit, given either as amount or as money in the currency
+// of the transfer, and returns it
+func validateCreditAmount(credit *pb.MultiLegCredit, currency string, field func(name string) string) (int64, []*errdetails.BadRequest_FieldViolation) {
+	money := credit.GetMoney()
+	if money == nil {
+		if credit.GetAmount() <= 0 {
+			return 0, []*errdetails.BadRequest_FieldViolation{fieldViolation(field("amount"), invalidField("gt", "must be greater than 0"))}
+		}
+		return credit.GetAmount(), nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	if credit.GetAmount() != 0 {
+		violations = append(violations, fieldViolation(field("money"), invalidField("excluded_with", "must not be set along with amount")))
+	}
+
+	if money.GetCurrency() != currency {
+		return 0, append(violations, fieldViolation(field("money.currency"), invalidField("eqfield", "must be the currency of the transfer")))
+	}
+
+	amount, err := util.ParseMoney(money.GetAmount(), currency)
+	if err != nil {
+		return 0, append(violations, fieldViolation(field("money.amount"), err))
+	}
+	if amount.Amount <= 0 {
+		violations = append(violations, fieldViolation(field("money.amount"), invalidField("gt", "must be greater than 0")))
+	}
+
+	return amount.Amount, violations
+}
+
+// creditAmount returns the amount of a credit that passed validation, in the currency of the transfer
+func creditAmount(credit *pb.MultiLegCredit, currency string) int64 {
+	if money := credit.GetMoney(); money != nil {
+		amount, _ := util.ParseMoney(money.GetAmount(), currency)
+		return amount.Amount
+	}
+	return credit.GetAmount()
+}
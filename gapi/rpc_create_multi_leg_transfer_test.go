@@ -0,0 +1,298 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateMultiLegTransferAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	buyer := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	seller := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 0}
+	platform := db.Account{AccountID: 3, Owner: util.RandomOwner(), Currency: util.USD, Balance: 0}
+	cadAccount := db.Account{AccountID: 4, Owner: user2, Currency: util.CAD, Balance: 0}
+
+	req := &pb.CreateMultiLegTransferRequest{
+		FromAccountId: buyer.AccountID,
+		Currency:      util.USD,
+		Credits: []*pb.MultiLegCredit{
+			{AccountId: seller.AccountID, Amount: 90, Memo: "seller"},
+			{AccountId: platform.AccountID, Amount: 10, Memo: "platform fee"},
+		},
+	}
+	accountIDs := []int64{buyer.AccountID, seller.AccountID, platform.AccountID}
+
+	transactionID := pgtype.Int8{Int64: 5, Valid: true}
+	result := db.MultiLegTransferTxResult{
+		Transaction: db.Transaction{ID: 5, SourceAccountID: buyer.AccountID, Amount: 100},
+		Legs: []db.TransactionLeg{
+			{TransactionID: 5, Position: 0, AccountID: buyer.AccountID, Amount: -100},
+			{TransactionID: 5, Position: 1, AccountID: seller.AccountID, Amount: 90, Memo: "seller"},
+			{TransactionID: 5, Position: 2, AccountID: platform.AccountID, Amount: 10, Memo: "platform fee"},
+		},
+		Entries: []db.Entry{
+			{ID: 1, AccountID: buyer.AccountID, TransactionID: transactionID, Amount: -100, Balance: 900},
+			{ID: 2, AccountID: seller.AccountID, TransactionID: transactionID, Amount: 90, Balance: 90},
+			{ID: 3, AccountID: platform.AccountID, TransactionID: transactionID, Amount: 10, Balance: 10},
+		},
+		FromAccount: db.Account{AccountID: buyer.AccountID, Owner: user1, Currency: util.USD, Balance: 900},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateMultiLegTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.MultiLegTransferTxParams{
+					FromAccountID: buyer.AccountID,
+					Credits: []db.MultiLegCredit{
+						{AccountID: seller.AccountID, Amount: 90, Memo: "seller"},
+						{AccountID: platform.AccountID, Amount: 10, Memo: "platform fee"},
+					},
+				}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Eq(accountIDs)).Times(1).Return([]db.Account{buyer, seller, platform}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				require.NoError(t, err)
+
+				transaction := res.GetTransaction()
+				require.Equal(t, int64(100), transaction.GetAmount())
+				require.Zero(t, transaction.GetDestinationAccountId())
+				require.Len(t, transaction.GetLegs(), 3)
+
+				var sum int64
+				for i, leg := range transaction.GetLegs() {
+					require.Equal(t, int32(i), leg.GetPosition())
+					sum += leg.GetAmount()
+				}
+				require.Zero(t, sum)
+				require.Equal(t, "platform fee", transaction.GetLegs()[2].GetMemo())
+
+				require.Len(t, res.GetEntries(), 3)
+				require.Equal(t, int64(900), res.GetFromAccount().GetBalance())
+			},
+		},
+		{
+			name: "OK With Money",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits: []*pb.MultiLegCredit{
+					{AccountId: seller.AccountID, Money: &pb.Money{Amount: "0.90", Currency: util.USD}, Memo: "seller"},
+					{AccountId: platform.AccountID, Amount: 10, Memo: "platform fee"},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.MultiLegTransferTxParams{
+					FromAccountID: buyer.AccountID,
+					Credits: []db.MultiLegCredit{
+						{AccountID: seller.AccountID, Amount: 90, Memo: "seller"},
+						{AccountID: platform.AccountID, Amount: 10, Memo: "platform fee"},
+					},
+				}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Eq(accountIDs)).Times(1).Return([]db.Account{buyer, seller, platform}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Credit Money In Another Currency",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits:       []*pb.MultiLegCredit{{AccountId: seller.AccountID, Money: &pb.Money{Amount: "0.90", Currency: util.EUR}}},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{buyer, seller, platform}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name:     "Account Not Found",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{buyer, seller}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name: "Currency Mismatch",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits:       []*pb.MultiLegCredit{{AccountId: cadAccount.AccountID, Amount: 10}},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{buyer, cadAccount}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Duplicate Credit",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits: []*pb.MultiLegCredit{
+					{AccountId: seller.AccountID, Amount: 10},
+					{AccountId: seller.AccountID, Amount: 20},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Credit To Source",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits:       []*pb.MultiLegCredit{{AccountId: buyer.AccountID, Amount: 10}},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Total Overflow",
+			req: &pb.CreateMultiLegTransferRequest{
+				FromAccountId: buyer.AccountID,
+				Currency:      util.USD,
+				Credits: []*pb.MultiLegCredit{
+					{AccountId: seller.AccountID, Amount: math.MaxInt64},
+					{AccountId: platform.AccountID, Amount: 1},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "No Credits",
+			req:      &pb.CreateMultiLegTransferRequest{FromAccountId: buyer.AccountID, Currency: util.USD},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Insufficient Funds",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				fundsErr := &db.InsufficientFundsError{AccountID: buyer.AccountID, Available: 50, Requested: 100}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{buyer, seller, platform}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.MultiLegTransferTxResult{}, fundsErr)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{buyer, seller, platform}, nil)
+				store.EXPECT().MultiLegTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.MultiLegTransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateMultiLegTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateMultiLegTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
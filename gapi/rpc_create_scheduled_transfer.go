@@ -0,0 +1,80 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateScheduledTransfer(ctx context.Context, req *pb.CreateScheduledTransferRequest) (*pb.CreateScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateScheduledTransferRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	amount := requestAmount(req)
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	if _, err := server.validAccount(ctx, req.GetToAccountId(), amount.Currency); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := server.store.CreateScheduledTransfer(ctx, db.CreateScheduledTransferParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        amount.Amount,
+		ExecuteAt:     pgtype.Timestamptz{Time: req.GetExecuteAt().AsTime(), Valid: true},
+	})
+	if err != nil {
+		return nil, storeError(err, "failed to create scheduled transfer")
+	}
+
+	rsp := &pb.CreateScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
+
+func validateCreateScheduledTransferRequest(req *pb.CreateScheduledTransferRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := validateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if req.GetFromAccountId() != 0 && req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", invalidField("nefield", "must differ from from_account_id")))
+	}
+
+	violations = append(violations, validateAmount(req, currencies, requestField)...)
+
+	if req.ExecuteAt == nil {
+		violations = append(violations, fieldViolation("execute_at", invalidField("required", "is required")))
+	} else if err := req.GetExecuteAt().CheckValid(); err != nil {
+		violations = append(violations, fieldViolation("execute_at", err))
+	} else if !req.GetExecuteAt().AsTime().After(time.Now()) {
+		violations = append(violations, fieldViolation("execute_at", invalidField("gt", "must be in the future")))
+	}
+
+	return violations
+}
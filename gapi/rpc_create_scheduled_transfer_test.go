@@ -0,0 +1,230 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateScheduledTransferAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	cadAccount := db.Account{AccountID: 3, Owner: user2, Currency: util.CAD, Balance: 500}
+	amount := int64(300)
+	executeAt := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	req := &pb.CreateScheduledTransferRequest{
+		FromAccountId: account.AccountID,
+		ToAccountId:   toAccount.AccountID,
+		Amount:        amount,
+		Currency:      util.USD,
+		ExecuteAt:     timestamppb.New(executeAt),
+	}
+
+	scheduled := db.ScheduledTransfer{
+		ID:            1,
+		FromAccountID: account.AccountID,
+		ToAccountID:   toAccount.AccountID,
+		Amount:        amount,
+		ExecuteAt:     pgtype.Timestamptz{Time: executeAt, Valid: true},
+		Status:        db.ScheduledTransferStatusPending,
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateScheduledTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateScheduledTransferParams{
+					FromAccountID: account.AccountID,
+					ToAccountID:   toAccount.AccountID,
+					Amount:        amount,
+					ExecuteAt:     pgtype.Timestamptz{Time: executeAt.UTC(), Valid: true},
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).Times(1).Return(scheduled, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.ScheduledTransferStatusPending, res.GetScheduledTransfer().GetStatus())
+				require.Equal(t, amount, res.GetScheduledTransfer().GetAmount())
+				require.True(t, executeAt.Equal(res.GetScheduledTransfer().GetExecuteAt().AsTime()))
+				require.Nil(t, res.GetScheduledTransfer().GetResolvedAt())
+			},
+		},
+		{
+			name: "OK With Money",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Money:         &pb.Money{Amount: "3", Currency: util.USD},
+				ExecuteAt:     timestamppb.New(executeAt),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateScheduledTransferParams{
+					FromAccountID: account.AccountID,
+					ToAccountID:   toAccount.AccountID,
+					Amount:        amount,
+					ExecuteAt:     pgtype.Timestamptz{Time: executeAt.UTC(), Valid: true},
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Eq(arg)).Times(1).Return(scheduled, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Currency Mismatch",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   cadAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				ExecuteAt:     timestamppb.New(executeAt),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cadAccount.AccountID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Missing Execute At",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Past Execute At",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				ExecuteAt:     timestamppb.New(time.Now().Add(-time.Minute)),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Same Account",
+			req: &pb.CreateScheduledTransferRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   account.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				ExecuteAt:     timestamppb.New(executeAt),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateScheduledTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.ScheduledTransfer{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateScheduledTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateScheduledTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
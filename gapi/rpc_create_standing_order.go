@@ -0,0 +1,126 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) CreateStandingOrder(ctx context.Context, req *pb.CreateStandingOrderRequest) (*pb.CreateStandingOrderResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	startAt := time.Now()
+	if req.StartAt != nil {
+		startAt = req.GetStartAt().AsTime()
+	}
+
+	var schedule util.Schedule
+	if violations := validateCreateStandingOrderRequest(req, server.currencies, startAt, &schedule); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+	amount := requestAmount(req)
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	if _, err := server.validAccount(ctx, req.GetToAccountId(), amount.Currency); err != nil {
+		return nil, err
+	}
+
+	arg := db.CreateStandingOrderParams{
+		FromAccountID:           req.GetFromAccountId(),
+		ToAccountID:             req.GetToAccountId(),
+		Amount:                  amount.Amount,
+		Schedule:                req.GetSchedule(),
+		StartAt:                 pgtype.Timestamptz{Time: startAt, Valid: true},
+		InsufficientFundsPolicy: db.InsufficientFundsPolicySkip,
+		NextRunAt:               pgtype.Timestamptz{Time: schedule.First(), Valid: true},
+	}
+	if req.EndAt != nil {
+		arg.EndAt = pgtype.Timestamptz{Time: req.GetEndAt().AsTime(), Valid: true}
+	}
+	if req.GetMaxOccurrences() > 0 {
+		arg.MaxOccurrences = pgtype.Int4{Int32: req.GetMaxOccurrences(), Valid: true}
+	}
+	if req.GetInsufficientFundsPolicy() != "" {
+		arg.InsufficientFundsPolicy = req.GetInsufficientFundsPolicy()
+	}
+
+	order, err := server.store.CreateStandingOrder(ctx, arg)
+	if err != nil {
+		return nil, storeError(err, "failed to create standing order")
+	}
+
+	rsp := &pb.CreateStandingOrderResponse{
+		StandingOrder: convertStandingOrder(order),
+	}
+	return rsp, nil
+}
+
+func validateCreateStandingOrderRequest(req *pb.CreateStandingOrderRequest, currencies *util.CurrencyRegistry, startAt time.Time, schedule *util.Schedule) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := validateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	if req.GetFromAccountId() != 0 && req.GetFromAccountId() == req.GetToAccountId() {
+		violations = append(violations, fieldViolation("to_account_id", invalidField("nefield", "must differ from from_account_id")))
+	}
+
+	violations = append(violations, validateAmount(req, currencies, requestField)...)
+
+	if req.StartAt != nil {
+		if err := req.GetStartAt().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("start_at", err))
+			return violations
+		}
+		if !startAt.After(time.Now()) {
+			violations = append(violations, fieldViolation("start_at", invalidField("gt", "must be in the future")))
+		}
+	}
+
+	var err error
+	*schedule, err = util.ParseSchedule(req.GetSchedule(), startAt)
+	if err != nil {
+		violations = append(violations, fieldViolation("schedule", err))
+	}
+
+	if req.EndAt != nil {
+		if err := req.GetEndAt().CheckValid(); err != nil {
+			violations = append(violations, fieldViolation("end_at", err))
+		} else if first := schedule.First(); !first.IsZero() && req.GetEndAt().AsTime().Before(first) {
+			violations = append(violations, fieldViolation("end_at", invalidField("gtefield", "must not be before the first occurrence at %s", first.Format(time.RFC3339))))
+		}
+	}
+
+	if req.GetMaxOccurrences() < 0 {
+		violations = append(violations, fieldViolation("max_occurrences", invalidField("min", "must not be negative")))
+	}
+
+	switch req.GetInsufficientFundsPolicy() {
+	case "", db.InsufficientFundsPolicySkip, db.InsufficientFundsPolicyRetry:
+	default:
+		violations = append(violations, fieldViolation("insufficient_funds_policy", invalidField("oneof", "must be %s or %s", db.InsufficientFundsPolicySkip, db.InsufficientFundsPolicyRetry)))
+	}
+
+	return violations
+}
@@ -0,0 +1,246 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateStandingOrderAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	amount := int64(300)
+	startAt := time.Now().Add(24 * time.Hour).Truncate(time.Second).UTC()
+
+	req := &pb.CreateStandingOrderRequest{
+		FromAccountId: account.AccountID,
+		ToAccountId:   toAccount.AccountID,
+		Amount:        amount,
+		Currency:      util.USD,
+		Schedule:      util.ScheduleMonthly,
+		StartAt:       timestamppb.New(startAt),
+	}
+
+	order := db.StandingOrder{
+		ID:                      1,
+		FromAccountID:           account.AccountID,
+		ToAccountID:             toAccount.AccountID,
+		Amount:                  amount,
+		Schedule:                util.ScheduleMonthly,
+		StartAt:                 pgtype.Timestamptz{Time: startAt, Valid: true},
+		InsufficientFundsPolicy: db.InsufficientFundsPolicySkip,
+		Status:                  db.StandingOrderStatusActive,
+		NextRunAt:               pgtype.Timestamptz{Time: startAt, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateStandingOrderRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateStandingOrderResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateStandingOrderParams{
+					FromAccountID:           account.AccountID,
+					ToAccountID:             toAccount.AccountID,
+					Amount:                  amount,
+					Schedule:                util.ScheduleMonthly,
+					StartAt:                 pgtype.Timestamptz{Time: startAt, Valid: true},
+					InsufficientFundsPolicy: db.InsufficientFundsPolicySkip,
+					NextRunAt:               pgtype.Timestamptz{Time: startAt, Valid: true},
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Eq(arg)).Times(1).Return(order, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.StandingOrderStatusActive, res.GetStandingOrder().GetStatus())
+				require.True(t, startAt.Equal(res.GetStandingOrder().GetNextRunAt().AsTime()))
+				require.Nil(t, res.GetStandingOrder().GetEndAt())
+			},
+		},
+		{
+			name: "Cron With Limits",
+			req: &pb.CreateStandingOrderRequest{
+				FromAccountId:           account.AccountID,
+				ToAccountId:             toAccount.AccountID,
+				Amount:                  amount,
+				Currency:                util.USD,
+				Schedule:                "0 9 1 * *",
+				EndAt:                   timestamppb.New(time.Now().AddDate(1, 0, 0)),
+				MaxOccurrences:          6,
+				InsufficientFundsPolicy: db.InsufficientFundsPolicyRetry,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					CreateStandingOrder(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateStandingOrderParams) (db.StandingOrder, error) {
+						next := arg.NextRunAt.Time
+						require.Equal(t, 1, next.Day())
+						require.Equal(t, 9, next.Hour())
+						require.True(t, next.After(time.Now()))
+						require.True(t, arg.EndAt.Valid)
+						require.Equal(t, pgtype.Int4{Int32: 6, Valid: true}, arg.MaxOccurrences)
+						require.Equal(t, db.InsufficientFundsPolicyRetry, arg.InsufficientFundsPolicy)
+						return order, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Invalid Schedule",
+			req: &pb.CreateStandingOrderRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Schedule:      "yearly",
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "End Before First Occurrence",
+			req: &pb.CreateStandingOrderRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Schedule:      util.ScheduleDaily,
+				StartAt:       timestamppb.New(startAt),
+				EndAt:         timestamppb.New(startAt.Add(-time.Hour)),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Past Start",
+			req: &pb.CreateStandingOrderRequest{
+				FromAccountId: account.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Schedule:      util.ScheduleDaily,
+				StartAt:       timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Invalid Policy",
+			req: &pb.CreateStandingOrderRequest{
+				FromAccountId:           account.AccountID,
+				ToAccountId:             toAccount.AccountID,
+				Amount:                  amount,
+				Currency:                util.USD,
+				Schedule:                util.ScheduleWeekly,
+				InsufficientFundsPolicy: "wait",
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(1).Return(db.StandingOrder{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateStandingOrderResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateStandingOrder(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
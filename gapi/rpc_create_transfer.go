@@ -0,0 +1,226 @@
+package gapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/fx"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	idempotencyKeyHeader    = "idempotency-key"
+	maxIdempotencyKeyLength = 255
+)
+
+// transferFingerprint matches the JSON body of POST /transactions with the amount in minor units,
+// so an idempotency key fingerprints a transfer the same way on both APIs and whichever way the amount is given.
+type transferFingerprint struct {
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Convert       bool   `json:"convert,omitempty"`
+}
+
+func (server *Server) CreateTransfer(ctx context.Context, req *pb.CreateTransferRequest) (*pb.CreateTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateTransferRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	amount := requestAmount(req)
+
+	idempotency, err := server.idempotencyParams(ctx, authPayload.Username, req, amount)
+	if err != nil {
+		return nil, err
+	}
+	if idempotency != nil {
+		if rsp, err := server.replayIdempotentRequest(ctx, idempotency); rsp != nil || err != nil {
+			return rsp, err
+		}
+	}
+
+	fromAccount, err := server.validAccount(ctx, req.GetFromAccountId(), amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "from account doesn't belong to the authenticated user")
+	}
+
+	var conversion *db.Conversion
+	if req.GetConvert() {
+		toAccount, err := server.store.GetAccount(ctx, req.GetToAccountId())
+		if err != nil {
+			return nil, storeError(err, fmt.Sprintf("failed to get account %d", req.GetToAccountId()))
+		}
+
+		conversion, err = server.conversion(ctx, amount.Currency, toAccount.Currency, amount.Amount)
+		if err != nil {
+			return nil, err
+		}
+	} else if _, err := server.validAccount(ctx, req.GetToAccountId(), amount.Currency); err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferTxParams{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        amount.Amount,
+		Conversion:    conversion,
+		Idempotency:   idempotency,
+	}
+
+	result, err := server.store.TransferTx(ctx, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrIdempotencyKeyInUse) {
+			if rsp, err := server.replayIdempotentRequest(ctx, idempotency); rsp != nil || err != nil {
+				return rsp, err
+			}
+		}
+		return nil, storeError(err, "failed to transfer")
+	}
+
+	return convertTransferResult(result), nil
+}
+
+func (server *Server) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := server.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return account, storeError(err, fmt.Sprintf("failed to get account %d", accountID))
+	}
+
+	if account.Currency != currency {
+		return account, status.Errorf(codes.InvalidArgument, "account %d currency mismatch", accountID)
+	}
+
+	return account, nil
+}
+
+// conversion quotes the exchange of amount from one currency to another at the current rate, spread included.
+// It returns nil when both currencies are the same, as there is nothing to convert.
+func (server *Server) conversion(ctx context.Context, from, to string, amount int64) (*db.Conversion, error) {
+	if from == to {
+		return nil, nil
+	}
+
+	if server.rateProvider == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "currency conversion is not enabled")
+	}
+
+	rate, err := server.rateProvider.Rate(ctx, from, to)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot convert %s to %s: %s", from, to, err)
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to get exchange rate: %s", err)
+	}
+
+	destinationAmount, err := rate.Convert(amount, from, to)
+	if err != nil {
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+	}
+	if destinationAmount <= 0 {
+		err := fmt.Errorf("too small to convert from %s to %s", from, to)
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation("amount", err)})
+	}
+
+	return &db.Conversion{Rate: rate, DestinationAmount: destinationAmount}, nil
+}
+
+// idempotencyParams reads the idempotency-key metadata and fingerprints the request.
+// It returns nil params when the client did not send a key.
+func (server *Server) idempotencyParams(ctx context.Context, username string, req *pb.CreateTransferRequest, amount util.Money) (*db.IdempotencyParams, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(idempotencyKeyHeader)
+	if len(values) == 0 || values[0] == "" {
+		return nil, nil
+	}
+
+	key := values[0]
+	if len(key) > maxIdempotencyKeyLength {
+		err := invalidField("max", "must be at most %d characters", maxIdempotencyKeyLength)
+		return nil, invalidArgumentError([]*errdetails.BadRequest_FieldViolation{fieldViolation(idempotencyKeyHeader, err)})
+	}
+
+	fingerprint, err := json.Marshal(transferFingerprint{
+		FromAccountID: req.GetFromAccountId(),
+		ToAccountID:   req.GetToAccountId(),
+		Amount:        amount.Amount,
+		Currency:      amount.Currency,
+		Convert:       req.GetConvert(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to fingerprint request: %s", err)
+	}
+	sum := sha256.Sum256(fingerprint)
+
+	return &db.IdempotencyParams{
+		Username:       username,
+		Key:            key,
+		RequestHash:    hex.EncodeToString(sum[:]),
+		ExpiresAt:      time.Now().Add(server.config.IdempotencyKeyTTL),
+		RenderResponse: renderTransferResponse,
+	}, nil
+}
+
+// renderTransferResponse encodes the response of a transfer as it is stored under its idempotency key
+func renderTransferResponse(result db.TransferTxResult) ([]byte, error) {
+	return proto.Marshal(convertTransferResult(result))
+}
+
+// replayIdempotentRequest returns the stored response of an earlier request with the same key.
+// It returns neither a response nor an error if the key has not been used yet.
+func (server *Server) replayIdempotentRequest(ctx context.Context, params *db.IdempotencyParams) (*pb.CreateTransferResponse, error) {
+	entry, err := server.store.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Username: params.Username,
+		Key:      params.Key,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get idempotency key: %s", err)
+	}
+
+	if entry.RequestHash != params.RequestHash {
+		return nil, errorWithInfo(codes.FailedPrecondition, "idempotency key was already used with a different request", reasonIdempotencyKeyReused, nil)
+	}
+
+	rsp := &pb.CreateTransferResponse{}
+	if err := proto.Unmarshal(entry.ResponseBody, rsp); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to decode stored response: %s", err)
+	}
+
+	return rsp, nil
+}
+
+func validateCreateTransferRequest(req *pb.CreateTransferRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetFromAccountId()); err != nil {
+		violations = append(violations, fieldViolation("from_account_id", err))
+	}
+
+	if err := validateID(req.GetToAccountId()); err != nil {
+		violations = append(violations, fieldViolation("to_account_id", err))
+	}
+
+	return append(violations, validateAmount(req, currencies, requestField)...)
+}
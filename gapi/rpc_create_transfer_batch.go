@@ -0,0 +1,151 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchTransfers bounds the number of transfers of a batch, and so how long an atomic batch holds its locks
+const maxBatchTransfers = 100
+
+func (server *Server) CreateTransferBatch(ctx context.Context, req *pb.CreateTransferBatchRequest) (*pb.CreateTransferBatchResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateCreateTransferBatchRequest(req, server.currencies); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if err := server.validBatchAccounts(ctx, req.GetTransfers(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	arg := db.TransferBatchTxParams{
+		Owner: authPayload.Username,
+		Mode:  req.GetMode(),
+	}
+	for _, transfer := range req.GetTransfers() {
+		arg.Transfers = append(arg.Transfers, db.BatchTransfer{
+			FromAccountID: transfer.GetFromAccountId(),
+			ToAccountID:   transfer.GetToAccountId(),
+			Amount:        requestAmount(transfer).Amount,
+		})
+	}
+
+	result, err := server.store.TransferBatchTx(ctx, arg)
+	if err != nil {
+		return nil, transferBatchError(storeError(err, "failed to transfer batch"), result.Batch.ID)
+	}
+
+	rsp := &pb.CreateTransferBatchResponse{
+		Batch: convertTransferBatch(result.Batch, result.Items),
+	}
+	return rsp, nil
+}
+
+// transferBatchError adds the id of the batch that failed to the ErrorInfo metadata of err, so clients can
+// look it up. Errors without an ErrorInfo are given one with reason TRANSFER_BATCH_FAILED.
+func transferBatchError(err error, batchID int64) error {
+	if batchID == 0 {
+		return err
+	}
+
+	st := status.Convert(err)
+	info := &errdetails.ErrorInfo{Reason: reasonTransferBatchFailed}
+	for _, detail := range st.Details() {
+		if detail, ok := detail.(*errdetails.ErrorInfo); ok {
+			info = detail
+		}
+	}
+
+	metadata := map[string]string{"batch_id": strconv.FormatInt(batchID, 10)}
+	for key, value := range info.GetMetadata() {
+		metadata[key] = value
+	}
+
+	return errorWithInfo(st.Code(), st.Message(), info.GetReason(), metadata)
+}
+
+// validBatchAccounts checks upfront that every account of the batch exists in the currency of its transfers,
+// and that the user owns the accounts paying them, so a best effort batch only fails on what can change
+func (server *Server) validBatchAccounts(ctx context.Context, transfers []*pb.BatchTransferRequest, username string) error {
+	var accountIDs []int64
+	for _, transfer := range transfers {
+		accountIDs = append(accountIDs, transfer.GetFromAccountId(), transfer.GetToAccountId())
+	}
+
+	found, err := server.store.ListAccountsByID(ctx, accountIDs)
+	if err != nil {
+		return storeError(err, "failed to get accounts")
+	}
+
+	accounts := make(map[int64]db.Account, len(found))
+	for _, account := range found {
+		accounts[account.AccountID] = account
+	}
+
+	for _, transfer := range transfers {
+		for _, accountID := range []int64{transfer.GetFromAccountId(), transfer.GetToAccountId()} {
+			account, ok := accounts[accountID]
+			if !ok {
+				return status.Errorf(codes.NotFound, "failed to get account %d: %s", accountID, db.ErrRecordNotFound)
+			}
+
+			if account.Currency != requestAmount(transfer).Currency {
+				return status.Errorf(codes.InvalidArgument, "account %d currency mismatch", accountID)
+			}
+		}
+
+		if accounts[transfer.GetFromAccountId()].Owner != username {
+			return status.Errorf(codes.PermissionDenied, "from account %d doesn't belong to the authenticated user", transfer.GetFromAccountId())
+		}
+	}
+
+	return nil
+}
+
+func validateCreateTransferBatchRequest(req *pb.CreateTransferBatchRequest, currencies *util.CurrencyRegistry) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetMode() != db.TransferBatchModeAtomic && req.GetMode() != db.TransferBatchModeBestEffort {
+		err := invalidField("oneof", "must be %s or %s", db.TransferBatchModeAtomic, db.TransferBatchModeBestEffort)
+		violations = append(violations, fieldViolation("mode", err))
+	}
+
+	switch n := len(req.GetTransfers()); {
+	case n == 0:
+		violations = append(violations, fieldViolation("transfers", invalidField("required", "must not be empty")))
+	case n > maxBatchTransfers:
+		violations = append(violations, fieldViolation("transfers", invalidField("max", "must have at most %d transfers", maxBatchTransfers)))
+	}
+
+	for i, transfer := range req.GetTransfers() {
+		field := func(name string) string {
+			return fmt.Sprintf("transfers[%d].%s", i, name)
+		}
+
+		if err := validateID(transfer.GetFromAccountId()); err != nil {
+			violations = append(violations, fieldViolation(field("from_account_id"), err))
+		}
+
+		if err := validateID(transfer.GetToAccountId()); err != nil {
+			violations = append(violations, fieldViolation(field("to_account_id"), err))
+		}
+
+		if transfer.GetFromAccountId() != 0 && transfer.GetFromAccountId() == transfer.GetToAccountId() {
+			violations = append(violations, fieldViolation(field("to_account_id"), invalidField("nefield", "must differ from from_account_id")))
+		}
+
+		violations = append(violations, validateAmount(transfer, currencies, field)...)
+	}
+
+	return violations
+}
@@ -0,0 +1,274 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTransferBatchAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	account1 := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	account2 := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	account3 := db.Account{AccountID: 3, Owner: user2, Currency: util.CAD, Balance: 500}
+	accountIDs := []int64{account1.AccountID, account2.AccountID, account1.AccountID, account2.AccountID}
+
+	newRequest := func(mode string) *pb.CreateTransferBatchRequest {
+		return &pb.CreateTransferBatchRequest{
+			Mode: mode,
+			Transfers: []*pb.BatchTransferRequest{
+				{FromAccountId: account1.AccountID, ToAccountId: account2.AccountID, Amount: 300, Currency: util.USD},
+				{FromAccountId: account1.AccountID, ToAccountId: account2.AccountID, Amount: 900, Currency: util.USD},
+			},
+		}
+	}
+
+	arg := func(mode string) db.TransferBatchTxParams {
+		return db.TransferBatchTxParams{
+			Owner: user1,
+			Mode:  mode,
+			Transfers: []db.BatchTransfer{
+				{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 300},
+				{FromAccountID: account1.AccountID, ToAccountID: account2.AccountID, Amount: 900},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateTransferBatchRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateTransferBatchResponse, err error)
+	}{
+		{
+			name:     "Best Effort",
+			req:      newRequest(db.TransferBatchModeBestEffort),
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				result := db.TransferBatchTxResult{
+					Batch: db.TransferBatch{ID: 7, Owner: user1, Mode: db.TransferBatchModeBestEffort, Status: db.TransferBatchStatusPartial},
+					Items: []db.TransferBatchItem{
+						{BatchID: 7, Position: 0, FromAccountID: 1, ToAccountID: 2, Amount: 300, Status: db.TransferBatchItemStatusSucceeded, TransactionID: pgtype.Int8{Int64: 11, Valid: true}},
+						{BatchID: 7, Position: 1, FromAccountID: 1, ToAccountID: 2, Amount: 900, Status: db.TransferBatchItemStatusFailed, Error: pgtype.Text{String: "insufficient funds", Valid: true}},
+					},
+				}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Eq(accountIDs)).Times(1).Return([]db.Account{account1, account2}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Eq(arg(db.TransferBatchModeBestEffort))).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				require.NoError(t, err)
+				batch := res.GetBatch()
+				require.Equal(t, int64(7), batch.GetId())
+				require.Equal(t, db.TransferBatchStatusPartial, batch.GetStatus())
+				require.Len(t, batch.GetItems(), 2)
+				require.Equal(t, int64(11), batch.GetItems()[0].GetTransactionId())
+				require.Equal(t, db.TransferBatchItemStatusFailed, batch.GetItems()[1].GetStatus())
+				require.Equal(t, "insufficient funds", batch.GetItems()[1].GetError())
+			},
+		},
+		{
+			name:     "Atomic Insufficient Funds",
+			req:      newRequest(db.TransferBatchModeAtomic),
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				fundsErr := &db.InsufficientFundsError{AccountID: account1.AccountID, Available: 700, Requested: 900}
+				result := db.TransferBatchTxResult{
+					Batch: db.TransferBatch{ID: 7, Owner: user1, Mode: db.TransferBatchModeAtomic, Status: db.TransferBatchStatusFailed},
+				}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Eq(accountIDs)).Times(1).Return([]db.Account{account1, account2}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Eq(arg(db.TransferBatchModeAtomic))).Times(1).Return(result, fundsErr)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, reasonInsufficientFunds, info.GetReason())
+				require.Equal(t, "700", info.GetMetadata()["available"])
+				require.Equal(t, "7", info.GetMetadata()["batch_id"])
+			},
+		},
+		{
+			name:     "Best Effort Stopped",
+			req:      newRequest(db.TransferBatchModeBestEffort),
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				result := db.TransferBatchTxResult{
+					Batch: db.TransferBatch{ID: 7, Owner: user1, Mode: db.TransferBatchModeBestEffort, Status: db.TransferBatchStatusPartial},
+				}
+
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Eq(accountIDs)).Times(1).Return([]db.Account{account1, account2}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Eq(arg(db.TransferBatchModeBestEffort))).Times(1).Return(result, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, reasonTransferBatchFailed, info.GetReason())
+				require.Equal(t, "7", info.GetMetadata()["batch_id"])
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      newRequest(db.TransferBatchModeAtomic),
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account1, account2}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      newRequest(db.TransferBatchModeAtomic),
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+		{
+			name: "Account Not Found",
+			req: &pb.CreateTransferBatchRequest{
+				Mode: db.TransferBatchModeAtomic,
+				Transfers: []*pb.BatchTransferRequest{
+					{FromAccountId: account1.AccountID, ToAccountId: 99, Amount: 300, Currency: util.USD},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account1}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name: "Currency Mismatch",
+			req: &pb.CreateTransferBatchRequest{
+				Mode: db.TransferBatchModeBestEffort,
+				Transfers: []*pb.BatchTransferRequest{
+					{FromAccountId: account1.AccountID, ToAccountId: account3.AccountID, Amount: 300, Currency: util.USD},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account1, account3}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Invalid Mode",
+			req:      newRequest("all_or_nothing"),
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Empty Batch",
+			req:      &pb.CreateTransferBatchRequest{Mode: db.TransferBatchModeAtomic},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Invalid Transfer",
+			req: &pb.CreateTransferBatchRequest{
+				Mode: db.TransferBatchModeAtomic,
+				Transfers: []*pb.BatchTransferRequest{
+					{FromAccountId: account1.AccountID, ToAccountId: account2.AccountID, Amount: 300, Currency: util.USD},
+					{FromAccountId: account1.AccountID, ToAccountId: account1.AccountID, Amount: -1, Currency: util.USD},
+				},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+				require.True(t, ok)
+
+				var fields []string
+				for _, violation := range badRequest.GetFieldViolations() {
+					fields = append(fields, violation.GetField())
+				}
+				require.Equal(t, []string{"transfers[1].to_account_id", "transfers[1].amount"}, fields)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      newRequest(db.TransferBatchModeBestEffort),
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccountsByID(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{account1, account2}, nil)
+				store.EXPECT().TransferBatchTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferBatchTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferBatchResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateTransferBatch(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
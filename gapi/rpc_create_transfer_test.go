@@ -0,0 +1,455 @@
+package gapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateTransferAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	fromAccount := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	cadAccount := db.Account{AccountID: 3, Owner: user2, Currency: util.CAD, Balance: 500}
+	jpyAccount := db.Account{AccountID: 4, Owner: user2, Currency: "JPY", Balance: 500}
+	amount := int64(10)
+
+	req := &pb.CreateTransferRequest{
+		FromAccountId: fromAccount.AccountID,
+		ToAccountId:   toAccount.AccountID,
+		Amount:        amount,
+		Currency:      util.USD,
+	}
+
+	result := db.TransferTxResult{
+		Transaction: db.Transaction{
+			ID:                   1,
+			SourceAccountID:      fromAccount.AccountID,
+			DestinationAccountID: pgtype.Int8{Int64: toAccount.AccountID, Valid: true},
+			Amount:               amount,
+		},
+		FromAccount: db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 990},
+		ToAccount:   db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 510},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.CreateTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.AccountID,
+					ToAccountID:   toAccount.AccountID,
+					Amount:        amount,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, result.Transaction.ID, res.GetTransaction().GetId())
+				require.Equal(t, result.FromAccount.Balance, res.GetFromAccount().GetBalance())
+				require.Equal(t, result.ToAccount.Balance, res.GetToAccount().GetBalance())
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name: "Currency Mismatch",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   cadAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cadAccount.AccountID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Decimal Amount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Money:         &pb.Money{Amount: "0.10", Currency: util.USD},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.AccountID,
+					ToAccountID:   toAccount.AccountID,
+					Amount:        amount,
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "9.90", res.GetFromAccount().GetFormattedBalance().GetAmount())
+				require.Equal(t, util.USD, res.GetFromAccount().GetFormattedBalance().GetCurrency())
+			},
+		},
+		{
+			name: "Invalid Decimal Amount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Money:         &pb.Money{Amount: "0.105", Currency: util.USD},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Decimal Amount Along With Amount",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Money:         &pb.Money{Amount: "0.10", Currency: util.USD},
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Conversion",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   cadAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Convert:       true,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				// 1.36 CAD per USD less a spread of 50 basis points
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.AccountID,
+					ToAccountID:   cadAccount.AccountID,
+					Amount:        amount,
+					Conversion:    &db.Conversion{Rate: 135_320_000, DestinationAmount: 13},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cadAccount.AccountID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Conversion To Currency Without Minor Unit",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   jpyAccount.AccountID,
+				Amount:        amount,
+				Currency:      util.USD,
+				Convert:       true,
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				// 10 cents at 149.5 JPY per USD less a spread of 50 basis points is 14 yen
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.AccountID,
+					ToAccountID:   jpyAccount.AccountID,
+					Amount:        amount,
+					Conversion:    &db.Conversion{Rate: 14_875_250_000, DestinationAmount: 14},
+				}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(jpyAccount.AccountID)).Times(1).Return(jpyAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Conversion Too Small",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: cadAccount.AccountID,
+				ToAccountId:   fromAccount.AccountID,
+				Amount:        1,
+				Currency:      util.CAD,
+				Convert:       true,
+			},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(cadAccount.AccountID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "From Account Not Found",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Transfer Error",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name:     "Insufficient Funds",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				fundsErr := &db.InsufficientFundsError{AccountID: fromAccount.AccountID, Available: 5, Requested: amount}
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, fundsErr)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				details := status.Convert(err).Details()
+				require.Len(t, details, 1)
+				info, ok := details[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, reasonInsufficientFunds, info.GetReason())
+				require.Equal(t, "5", info.GetMetadata()["available"])
+				require.Equal(t, "10", info.GetMetadata()["requested"])
+			},
+		},
+		{
+			name: "Invalid Arguments",
+			req: &pb.CreateTransferRequest{
+				FromAccountId: fromAccount.AccountID,
+				ToAccountId:   toAccount.AccountID,
+				Amount:        -amount,
+				Currency:      "XYZ",
+			},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.CreateTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func TestCreateTransferIdempotencyAPI(t *testing.T) {
+	user := util.RandomOwner()
+	key := util.RandomString(16)
+
+	req := &pb.CreateTransferRequest{
+		FromAccountId: 1,
+		ToAccountId:   2,
+		Amount:        10,
+		Currency:      util.USD,
+	}
+
+	// The body POST /transactions fingerprints for the same transfer.
+	fingerprint := []byte(`{"from_account_id":1,"to_account_id":2,"amount":10,"currency":"USD"}`)
+
+	fromAccount := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: util.RandomOwner(), Currency: util.USD, Balance: 500}
+	result := db.TransferTxResult{
+		Transaction: db.Transaction{ID: 9, SourceAccountID: 1, DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true}, Amount: 10},
+		FromAccount: fromAccount,
+		ToAccount:   toAccount,
+	}
+	// stored as rendered when the transfer ran, with the exchange rate it showed then
+	stored := convertTransferResult(result)
+	stored.Transaction.ExchangeRate = "1.5"
+	body, err := proto.Marshal(stored)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(t *testing.T, store *mockdb.MockStore, hash string)
+		checkResponse func(t *testing.T, res *pb.CreateTransferResponse, err error)
+	}{
+		{
+			name: "First Request",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, hash string) {
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(db.IdempotencyKey{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.NotNil(t, arg.Idempotency)
+						require.Equal(t, user, arg.Idempotency.Username)
+						require.Equal(t, key, arg.Idempotency.Key)
+						require.Equal(t, hash, arg.Idempotency.RequestHash)
+
+						rendered, err := arg.Idempotency.RenderResponse(result)
+						require.NoError(t, err)
+						var rsp pb.CreateTransferResponse
+						require.NoError(t, proto.Unmarshal(rendered, &rsp))
+						require.True(t, proto.Equal(convertTransferResult(result), &rsp))
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, result.Transaction.ID, res.GetTransaction().GetId())
+			},
+		},
+		{
+			name: "Replay",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, hash string) {
+				stored := db.IdempotencyKey{Username: user, Key: key, RequestHash: hash, ResponseBody: body}
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(stored, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				require.NoError(t, err)
+				require.True(t, proto.Equal(stored, res))
+			},
+		},
+		{
+			name: "Key Reused With Different Request",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore, hash string) {
+				stored := db.IdempotencyKey{Username: user, Key: key, RequestHash: "other", ResponseBody: body}
+				store.EXPECT().GetIdempotencyKey(gomock.Any(), gomock.Any()).Times(1).Return(stored, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.CreateTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, reasonIdempotencyKeyReused, info.GetReason())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			sum := sha256.Sum256(fingerprint)
+			tc.buildStubs(t, store, hex.EncodeToString(sum[:]))
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, store, server.tokenMaker, user)
+			ctx = metadata.NewIncomingContext(ctx, metadata.Join(
+				metadata.Pairs(idempotencyKeyHeader, key),
+				mustIncomingMetadata(ctx),
+			))
+
+			res, err := server.CreateTransfer(ctx, req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
+
+func mustIncomingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return md
+}
@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetHold(ctx context.Context, req *pb.GetHoldRequest) (*pb.GetHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateHoldID(req.GetHoldId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	hold, err := server.ownHold(ctx, req.GetHoldId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetHoldResponse{
+		Hold: convertHold(hold),
+	}
+	return rsp, nil
+}
+
+// ownHold returns the hold if it was placed on an account of username
+func (server *Server) ownHold(ctx context.Context, holdID int64, username string) (db.Hold, error) {
+	hold, err := server.store.GetHold(ctx, holdID)
+	if err != nil {
+		return hold, storeError(err, "failed to get hold")
+	}
+
+	account, err := server.store.GetAccount(ctx, hold.AccountID)
+	if err != nil {
+		return hold, storeError(err, "failed to get account")
+	}
+
+	if account.Owner != username {
+		return hold, status.Errorf(codes.PermissionDenied, "hold doesn't belong to the authenticated user")
+	}
+
+	return hold, nil
+}
+
+func validateHoldID(holdID int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(holdID); err != nil {
+		violations = append(violations, fieldViolation("hold_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetScheduledTransfer(ctx context.Context, req *pb.GetScheduledTransferRequest) (*pb.GetScheduledTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateScheduledTransferID(req.GetScheduledTransferId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	scheduled, err := server.ownScheduledTransfer(ctx, req.GetScheduledTransferId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetScheduledTransferResponse{
+		ScheduledTransfer: convertScheduledTransfer(scheduled),
+	}
+	return rsp, nil
+}
+
+// ownScheduledTransfer returns the scheduled transfer if it is paid from an account of username
+func (server *Server) ownScheduledTransfer(ctx context.Context, id int64, username string) (db.ScheduledTransfer, error) {
+	scheduled, err := server.store.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		return scheduled, storeError(err, "failed to get scheduled transfer")
+	}
+
+	account, err := server.store.GetAccount(ctx, scheduled.FromAccountID)
+	if err != nil {
+		return scheduled, storeError(err, "failed to get account")
+	}
+
+	if account.Owner != username {
+		return scheduled, status.Errorf(codes.PermissionDenied, "scheduled transfer doesn't belong to the authenticated user")
+	}
+
+	return scheduled, nil
+}
+
+func validateScheduledTransferID(id int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(id); err != nil {
+		violations = append(violations, fieldViolation("scheduled_transfer_id", err))
+	}
+
+	return violations
+}
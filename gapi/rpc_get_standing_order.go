@@ -0,0 +1,59 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetStandingOrder(ctx context.Context, req *pb.GetStandingOrderRequest) (*pb.GetStandingOrderResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateStandingOrderID(req.GetStandingOrderId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	order, err := server.ownStandingOrder(ctx, req.GetStandingOrderId(), authPayload.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.GetStandingOrderResponse{
+		StandingOrder: convertStandingOrder(order),
+	}
+	return rsp, nil
+}
+
+// ownStandingOrder returns the standing order if it pays from an account of username
+func (server *Server) ownStandingOrder(ctx context.Context, id int64, username string) (db.StandingOrder, error) {
+	order, err := server.store.GetStandingOrder(ctx, id)
+	if err != nil {
+		return order, storeError(err, "failed to get standing order")
+	}
+
+	account, err := server.store.GetAccount(ctx, order.FromAccountID)
+	if err != nil {
+		return order, storeError(err, "failed to get account")
+	}
+
+	if account.Owner != username {
+		return order, status.Errorf(codes.PermissionDenied, "standing order doesn't belong to the authenticated user")
+	}
+
+	return order, nil
+}
+
+func validateStandingOrderID(id int64) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(id); err != nil {
+		violations = append(violations, fieldViolation("standing_order_id", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.GetTransactionResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateGetTransactionRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transaction, err := server.store.GetTransaction(ctx, req.GetTransactionId())
+	if err != nil {
+		return nil, storeError(err, "failed to get transaction")
+	}
+
+	rsp := &pb.GetTransactionResponse{
+		Transaction: convertTransaction(transaction),
+	}
+
+	accountIDs := []int64{transaction.SourceAccountID}
+	if transaction.DestinationAccountID.Valid {
+		accountIDs = append(accountIDs, transaction.DestinationAccountID.Int64)
+	} else {
+		legs, err := server.store.ListTransactionLegs(ctx, transaction.ID)
+		if err != nil {
+			return nil, storeError(err, "failed to list transaction legs")
+		}
+		for _, leg := range legs {
+			rsp.Transaction.Legs = append(rsp.Transaction.Legs, convertTransactionLeg(leg))
+			if leg.AccountID != transaction.SourceAccountID {
+				accountIDs = append(accountIDs, leg.AccountID)
+			}
+		}
+	}
+
+	for _, accountID := range accountIDs {
+		account, err := server.store.GetAccount(ctx, accountID)
+		if err != nil {
+			return nil, storeError(err, "failed to get account")
+		}
+		if account.Owner == authPayload.Username {
+			return rsp, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.PermissionDenied, "transaction doesn't involve an account of the authenticated user")
+}
+
+func validateGetTransactionRequest(req *pb.GetTransactionRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetTransactionId()); err != nil {
+		violations = append(violations, fieldViolation("transaction_id", err))
+	}
+
+	return violations
+}
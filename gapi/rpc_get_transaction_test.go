@@ -0,0 +1,167 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetTransactionAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	fromAccount := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 1000}
+	toAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 500}
+	transaction := db.Transaction{
+		ID:                   7,
+		SourceAccountID:      fromAccount.AccountID,
+		DestinationAccountID: pgtype.Int8{Int64: toAccount.AccountID, Valid: true},
+		Amount:               10,
+	}
+	multiLeg := db.Transaction{
+		ID:              8,
+		SourceAccountID: fromAccount.AccountID,
+		Amount:          10,
+	}
+	legs := []db.TransactionLeg{
+		{TransactionID: multiLeg.ID, Position: 0, AccountID: fromAccount.AccountID, Amount: -10},
+		{TransactionID: multiLeg.ID, Position: 1, AccountID: toAccount.AccountID, Amount: 7, Memo: "seller"},
+		{TransactionID: multiLeg.ID, Position: 2, AccountID: 3, Amount: 3, Memo: "fee"},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.GetTransactionRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.GetTransactionResponse, err error)
+	}{
+		{
+			name:     "OK Source Owner",
+			req:      &pb.GetTransactionRequest{TransactionId: transaction.ID},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(transaction, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(0)
+				store.EXPECT().ListTransactionLegs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, transaction.ID, res.GetTransaction().GetId())
+				require.Equal(t, toAccount.AccountID, res.GetTransaction().GetDestinationAccountId())
+				require.Empty(t, res.GetTransaction().GetLegs())
+			},
+		},
+		{
+			name:     "OK Destination Owner",
+			req:      &pb.GetTransactionRequest{TransactionId: transaction.ID},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(transaction, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, transaction.ID, res.GetTransaction().GetId())
+			},
+		},
+		{
+			name:     "OK Multi-Leg Recipient",
+			req:      &pb.GetTransactionRequest{TransactionId: multiLeg.ID},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(multiLeg.ID)).Times(1).Return(multiLeg, nil)
+				store.EXPECT().ListTransactionLegs(gomock.Any(), gomock.Eq(multiLeg.ID)).Times(1).Return(legs, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(3))).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, multiLeg.ID, res.GetTransaction().GetId())
+				require.Zero(t, res.GetTransaction().GetDestinationAccountId())
+				require.Len(t, res.GetTransaction().GetLegs(), len(legs))
+				for i, leg := range legs {
+					got := res.GetTransaction().GetLegs()[i]
+					require.Equal(t, leg.Position, got.GetPosition())
+					require.Equal(t, leg.AccountID, got.GetAccountId())
+					require.Equal(t, leg.Amount, got.GetAmount())
+					require.Equal(t, leg.Memo, got.GetMemo())
+				}
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.GetTransactionRequest{TransactionId: transaction.ID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(transaction, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.AccountID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.AccountID)).Times(1).Return(toAccount, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Not Found",
+			req:      &pb.GetTransactionRequest{TransactionId: transaction.ID},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(transaction.ID)).Times(1).Return(db.Transaction{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      &pb.GetTransactionRequest{TransactionId: transaction.ID},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Times(1).Return(db.Transaction{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			req:      &pb.GetTransactionRequest{},
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.GetTransactionResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+
+			res, err := server.GetTransaction(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (server *Server) GetTransferBatch(ctx context.Context, req *pb.GetTransferBatchRequest) (*pb.GetTransferBatchResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateGetTransferBatchRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	batch, err := server.store.GetTransferBatch(ctx, req.GetBatchId())
+	if err != nil {
+		return nil, storeError(err, "failed to get transfer batch")
+	}
+
+	if batch.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "transfer batch doesn't belong to the authenticated user")
+	}
+
+	items, err := server.store.ListTransferBatchItems(ctx, batch.ID)
+	if err != nil {
+		return nil, storeError(err, "failed to list transfer batch items")
+	}
+
+	rsp := &pb.GetTransferBatchResponse{
+		Batch: convertTransferBatch(batch, items),
+	}
+	return rsp, nil
+}
+
+func validateGetTransferBatchRequest(req *pb.GetTransferBatchRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetBatchId()); err != nil {
+		violations = append(violations, fieldViolation("batch_id", err))
+	}
+
+	return violations
+}
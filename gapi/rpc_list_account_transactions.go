@@ -0,0 +1,161 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	directionIncoming = "incoming"
+	directionOutgoing = "outgoing"
+)
+
+// transactionCursor marks the last transaction of a page in (created_at, id) order
+type transactionCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        int64     `json:"id"`
+}
+
+func (server *Server) ListAccountTransactions(ctx context.Context, req *pb.ListAccountTransactionsRequest) (*pb.ListAccountTransactionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor transactionCursor
+	if violations := validateListAccountTransactionsRequest(req, &cursor); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	account, err := server.store.GetAccount(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, storeError(err, "failed to get account")
+	}
+
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "account doesn't belong to the authenticated user")
+	}
+
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	arg := db.ListAccountTransactionsParams{
+		AccountID:       req.GetAccountId(),
+		IncludeOutgoing: req.GetDirection() != directionIncoming,
+		IncludeIncoming: req.GetDirection() != directionOutgoing,
+		// One extra row tells whether another page follows.
+		PageLimit: pageSize + 1,
+	}
+	if req.MinAmount != nil {
+		arg.MinAmount = pgtype.Int8{Int64: req.GetMinAmount(), Valid: true}
+	}
+	if req.MaxAmount != nil {
+		arg.MaxAmount = pgtype.Int8{Int64: req.GetMaxAmount(), Valid: true}
+	}
+	if req.CreatedFrom != nil {
+		arg.CreatedFrom = pgtype.Timestamptz{Time: req.GetCreatedFrom().AsTime(), Valid: true}
+	}
+	if req.CreatedTo != nil {
+		arg.CreatedTo = pgtype.Timestamptz{Time: req.GetCreatedTo().AsTime(), Valid: true}
+	}
+	if req.GetCursor() != "" {
+		arg.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		arg.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	transactions, err := server.store.ListAccountTransactions(ctx, arg)
+	if err != nil {
+		return nil, storeError(err, "failed to list transactions")
+	}
+
+	rsp := &pb.ListAccountTransactionsResponse{}
+	if len(transactions) > int(pageSize) {
+		transactions = transactions[:pageSize]
+		last := transactions[len(transactions)-1]
+		rsp.NextCursor, err = util.EncodeCursor(transactionCursor{
+			CreatedAt: last.Transaction.CreatedAt.Time,
+			ID:        last.Transaction.ID,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode cursor: %s", err)
+		}
+	}
+
+	var multiLegIDs []int64
+	multiLeg := make(map[int64]*pb.Transaction)
+	for _, row := range transactions {
+		transaction := convertTransaction(row.Transaction)
+		transaction.AccountAmount = row.AccountAmount
+		rsp.Transactions = append(rsp.Transactions, transaction)
+
+		if !row.Transaction.DestinationAccountID.Valid {
+			multiLegIDs = append(multiLegIDs, row.Transaction.ID)
+			multiLeg[row.Transaction.ID] = transaction
+		}
+	}
+
+	if len(multiLegIDs) > 0 {
+		legs, err := server.store.ListTransactionLegsByTransactions(ctx, multiLegIDs)
+		if err != nil {
+			return nil, storeError(err, "failed to list transaction legs")
+		}
+		for _, leg := range legs {
+			transaction := multiLeg[leg.TransactionID]
+			transaction.Legs = append(transaction.Legs, convertTransactionLeg(leg))
+		}
+	}
+
+	return rsp, nil
+}
+
+func validateListAccountTransactionsRequest(req *pb.ListAccountTransactionsRequest, cursor *transactionCursor) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetAccountId()); err != nil {
+		violations = append(violations, fieldViolation("account_id", err))
+	}
+
+	switch req.GetDirection() {
+	case "", directionIncoming, directionOutgoing:
+	default:
+		violations = append(violations, fieldViolation("direction", invalidField("oneof", "must be %q or %q", directionIncoming, directionOutgoing)))
+	}
+
+	if req.MinAmount != nil && req.GetMinAmount() <= 0 {
+		violations = append(violations, fieldViolation("min_amount", invalidField("gt", "must be positive")))
+	}
+	if req.MaxAmount != nil && req.GetMaxAmount() <= 0 {
+		violations = append(violations, fieldViolation("max_amount", invalidField("gt", "must be positive")))
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && req.GetMinAmount() > req.GetMaxAmount() {
+		violations = append(violations, fieldViolation("min_amount", invalidField("ltefield", "must not be greater than max_amount")))
+	}
+
+	if req.CreatedFrom != nil && req.CreatedTo != nil && !req.GetCreatedFrom().AsTime().Before(req.GetCreatedTo().AsTime()) {
+		violations = append(violations, fieldViolation("created_from", invalidField("ltfield", "must be before created_to")))
+	}
+
+	if req.GetCursor() != "" {
+		err := util.DecodeCursor(req.GetCursor(), cursor)
+		if err == nil && (cursor.ID <= 0 || cursor.CreatedAt.IsZero()) {
+			err = util.ErrInvalidCursor
+		}
+		if err != nil {
+			violations = append(violations, fieldViolation("cursor", err))
+		}
+	}
+
+	if err := validatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
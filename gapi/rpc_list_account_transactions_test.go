@@ -0,0 +1,286 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestListAccountTransactionsAPI(t *testing.T) {
+	user := util.RandomOwner()
+	account := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 123456000, time.UTC)
+	transactions := make([]db.ListAccountTransactionsRow, 3)
+	for i := range transactions {
+		transactions[i] = db.ListAccountTransactionsRow{
+			Transaction: db.Transaction{
+				ID:                   int64(10 - i),
+				SourceAccountID:      account.AccountID,
+				DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true},
+				Amount:               int64(i + 1),
+				CreatedAt:            pgtype.Timestamptz{Time: createdAt.Add(-time.Duration(i) * time.Second), Valid: true},
+			},
+			AccountAmount: int64(i + 1),
+		}
+	}
+
+	// account is credited 30 of the 50 account 3 paid to it and account 4
+	multiLeg := db.ListAccountTransactionsRow{
+		Transaction: db.Transaction{
+			ID:              11,
+			SourceAccountID: 3,
+			Amount:          50,
+			CreatedAt:       pgtype.Timestamptz{Time: createdAt.Add(time.Second), Valid: true},
+		},
+		AccountAmount: 30,
+	}
+	legs := []db.TransactionLeg{
+		{TransactionID: multiLeg.Transaction.ID, Position: 0, AccountID: 3, Amount: -50},
+		{TransactionID: multiLeg.Transaction.ID, Position: 1, AccountID: account.AccountID, Amount: 30},
+		{TransactionID: multiLeg.Transaction.ID, Position: 2, AccountID: 4, Amount: 20},
+	}
+
+	cursor, err := util.EncodeCursor(transactionCursor{
+		CreatedAt: transactions[1].Transaction.CreatedAt.Time,
+		ID:        transactions[1].Transaction.ID,
+	})
+	require.NoError(t, err)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListAccountTransactionsRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error)
+	}{
+		{
+			name:     "OK First Page",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, PageSize: 2},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountTransactionsParams{
+					AccountID:       account.AccountID,
+					IncludeOutgoing: true,
+					IncludeIncoming: true,
+					PageLimit:       3,
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transactions, nil)
+				store.EXPECT().ListTransactionLegsByTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransactions(), 2)
+				require.Equal(t, transactions[1].AccountAmount, res.GetTransactions()[1].GetAccountAmount())
+				require.Empty(t, res.GetTransactions()[1].GetLegs())
+				require.Equal(t, cursor, res.GetNextCursor())
+			},
+		},
+		{
+			name:     "OK Multi-Leg",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, Direction: directionIncoming, MinAmount: proto.Int64(25)},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountTransactionsParams{
+					AccountID:       account.AccountID,
+					IncludeIncoming: true,
+					MinAmount:       pgtype.Int8{Int64: 25, Valid: true},
+					PageLimit:       defaultPageSize + 1,
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return([]db.ListAccountTransactionsRow{multiLeg, transactions[0]}, nil)
+				store.EXPECT().ListTransactionLegsByTransactions(gomock.Any(), gomock.Eq([]int64{multiLeg.Transaction.ID})).Times(1).Return(legs, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetTransactions(), 2)
+
+				transaction := res.GetTransactions()[0]
+				require.Equal(t, multiLeg.Transaction.ID, transaction.GetId())
+				require.Equal(t, int64(50), transaction.GetAmount())
+				require.Equal(t, int64(30), transaction.GetAccountAmount())
+				require.Len(t, transaction.GetLegs(), len(legs))
+				require.Equal(t, account.AccountID, transaction.GetLegs()[1].GetAccountId())
+				require.Empty(t, res.GetTransactions()[1].GetLegs())
+			},
+		},
+		{
+			name:     "Legs Internal Error",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.ListAccountTransactionsRow{multiLeg}, nil)
+				store.EXPECT().ListTransactionLegsByTransactions(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name: "OK Filters And Cursor",
+			req: &pb.ListAccountTransactionsRequest{
+				AccountId:   account.AccountID,
+				Direction:   directionIncoming,
+				MinAmount:   proto.Int64(5),
+				MaxAmount:   proto.Int64(50),
+				CreatedFrom: timestamppb.New(from),
+				CreatedTo:   timestamppb.New(to),
+				Cursor:      cursor,
+			},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountTransactionsParams{
+					AccountID:       account.AccountID,
+					IncludeIncoming: true,
+					MinAmount:       pgtype.Int8{Int64: 5, Valid: true},
+					MaxAmount:       pgtype.Int8{Int64: 50, Valid: true},
+					CreatedFrom:     pgtype.Timestamptz{Time: from, Valid: true},
+					CreatedTo:       pgtype.Timestamptz{Time: to, Valid: true},
+					CursorCreatedAt: pgtype.Timestamptz{Time: transactions[1].Transaction.CreatedAt.Time, Valid: true},
+					CursorID:        pgtype.Int8{Int64: transactions[1].Transaction.ID, Valid: true},
+					PageLimit:       defaultPageSize + 1,
+				}
+
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				require.NoError(t, err)
+				require.Empty(t, res.GetTransactions())
+				require.Empty(t, res.GetNextCursor())
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Account Not Found",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name:     "Invalid Direction",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, Direction: "sideways"},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Inverted Amount Range",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, MinAmount: proto.Int64(50), MaxAmount: proto.Int64(5)},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Inverted Time Range",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, CreatedFrom: timestamppb.New(to), CreatedTo: timestamppb.New(from)},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Invalid Cursor",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, Cursor: "not-a-cursor"},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Invalid Page Size",
+			req:      &pb.ListAccountTransactionsRequest{AccountId: account.AccountID, PageSize: 101},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountTransactions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountTransactionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+
+			res, err := server.ListAccountTransactions(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
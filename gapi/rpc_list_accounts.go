@@ -0,0 +1,98 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultPageSize = 20
+
+// accountCursor marks the last account of a page in account_id order.
+// ListAccounts returns it as next_cursor, base64 encoded, and clients must treat it as opaque.
+type accountCursor struct {
+	AccountID int64 `json:"account_id"`
+}
+
+func (server *Server) ListAccounts(ctx context.Context, req *pb.ListAccountsRequest) (*pb.ListAccountsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor accountCursor
+	if violations := validateListAccountsRequest(req, server.currencies, &cursor); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	arg := db.ListAccountsParams{
+		Owner:          authPayload.Username,
+		AfterAccountID: cursor.AccountID,
+		// One extra row tells whether another page follows.
+		PageLimit: pageSize + 1,
+	}
+	if req.GetCurrency() != "" {
+		arg.Currency = pgtype.Text{String: req.GetCurrency(), Valid: true}
+	}
+	if req.MinBalance != nil {
+		arg.MinBalance = pgtype.Int8{Int64: req.GetMinBalance(), Valid: true}
+	}
+
+	accounts, err := server.store.ListAccounts(ctx, arg)
+	if err != nil {
+		return nil, storeError(err, "failed to list accounts")
+	}
+
+	rsp := &pb.ListAccountsResponse{}
+	if len(accounts) > int(pageSize) {
+		accounts = accounts[:pageSize]
+		rsp.NextCursor, err = util.EncodeCursor(accountCursor{AccountID: accounts[len(accounts)-1].AccountID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode cursor: %s", err)
+		}
+	}
+	for _, account := range accounts {
+		rsp.Accounts = append(rsp.Accounts, convertAccount(account))
+	}
+
+	return rsp, nil
+}
+
+func validateListAccountsRequest(req *pb.ListAccountsRequest, currencies *util.CurrencyRegistry, cursor *accountCursor) (violations []*errdetails.BadRequest_FieldViolation) {
+	if req.GetCurrency() != "" {
+		if err := validateCurrency(currencies, req.GetCurrency()); err != nil {
+			violations = append(violations, fieldViolation("currency", err))
+		}
+	}
+
+	if req.GetMinBalance() < 0 {
+		violations = append(violations, fieldViolation("min_balance", invalidField("min", "must not be negative")))
+	}
+
+	if req.GetCursor() != "" {
+		err := util.DecodeCursor(req.GetCursor(), cursor)
+		if err == nil && cursor.AccountID <= 0 {
+			err = util.ErrInvalidCursor
+		}
+		if err != nil {
+			violations = append(violations, fieldViolation("cursor", err))
+		}
+	}
+
+	if err := validatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
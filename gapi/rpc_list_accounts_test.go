@@ -0,0 +1,124 @@
+package gapi
+
+import (
+	"database/sql"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestListAccountsAPI(t *testing.T) {
+	user := util.RandomOwner()
+	accounts := []db.Account{
+		{AccountID: 1, Owner: user, Currency: util.USD, Balance: 100},
+		{AccountID: 2, Owner: user, Currency: util.EUR, Balance: 200},
+		{AccountID: 3, Owner: user, Currency: util.CAD, Balance: 300},
+	}
+
+	cursor, err := util.EncodeCursor(accountCursor{AccountID: accounts[0].AccountID})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListAccountsRequest
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ListAccountsResponse, err error)
+	}{
+		{
+			name: "OK Next Cursor",
+			req:  &pb.ListAccountsRequest{PageSize: 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Owner:     user,
+					PageLimit: 3,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), 2)
+
+				var next accountCursor
+				require.NoError(t, util.DecodeCursor(res.GetNextCursor(), &next))
+				require.Equal(t, accounts[1].AccountID, next.AccountID)
+			},
+		},
+		{
+			name: "OK Filters And Cursor",
+			req: &pb.ListAccountsRequest{
+				Currency:   util.EUR,
+				MinBalance: proto.Int64(100),
+				Cursor:     cursor,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListAccountsParams{
+					Owner:          user,
+					AfterAccountID: accounts[0].AccountID,
+					Currency:       pgtype.Text{String: util.EUR, Valid: true},
+					MinBalance:     pgtype.Int8{Int64: 100, Valid: true},
+					PageLimit:      defaultPageSize + 1,
+				}
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts[1:2], nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetAccounts(), 1)
+				require.Empty(t, res.GetNextCursor())
+			},
+		},
+		{
+			name: "Internal Error",
+			req:  &pb.ListAccountsRequest{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name: "Invalid Cursor",
+			req:  &pb.ListAccountsRequest{Cursor: "bm90LWpzb24"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name: "Invalid Page Size",
+			req:  &pb.ListAccountsRequest{PageSize: maxPageSize + 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListAccountsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := newContextWithBearerToken(t, store, server.tokenMaker, user)
+
+			res, err := server.ListAccounts(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
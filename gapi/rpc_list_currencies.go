@@ -0,0 +1,28 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	cacheControlHeader = "cache-control"
+	// currenciesCacheControl lets clients cache the currency list, which only changes with the config
+	currenciesCacheControl = "public, max-age=300"
+)
+
+// ListCurrencies serves the currencies accounts can be opened in, with the minor units amounts are given in.
+// It needs no authorization.
+func (server *Server) ListCurrencies(ctx context.Context, req *pb.ListCurrenciesRequest) (*pb.ListCurrenciesResponse, error) {
+	// the gateway sends it as the Cache-Control header; failing to set it only costs the caching
+	_ = grpc.SetHeader(ctx, metadata.Pairs(cacheControlHeader, currenciesCacheControl))
+
+	rsp := &pb.ListCurrenciesResponse{}
+	for _, currency := range server.currencies.Currencies() {
+		rsp.Currencies = append(rsp.Currencies, convertCurrency(currency))
+	}
+	return rsp, nil
+}
@@ -0,0 +1,71 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCurrenciesAPI(t *testing.T) {
+	testCases := []struct {
+		name          string
+		currencies    []string
+		checkResponse func(t *testing.T, currencies []*pb.Currency)
+	}{
+		{
+			name:       "Enabled Subset",
+			currencies: []string{"jpy", util.USD, util.EUR, util.USD},
+			checkResponse: func(t *testing.T, currencies []*pb.Currency) {
+				require.Len(t, currencies, 3)
+				require.Equal(t, util.EUR, currencies[0].GetCode())
+				require.Equal(t, "JPY", currencies[1].GetCode())
+				require.Equal(t, "392", currencies[1].GetNumericCode())
+				require.Equal(t, "Yen", currencies[1].GetName())
+				require.Zero(t, currencies[1].GetMinorUnits())
+				require.Equal(t, util.USD, currencies[2].GetCode())
+				require.Equal(t, int32(2), currencies[2].GetMinorUnits())
+			},
+		},
+		{
+			name: "All Currencies",
+			checkResponse: func(t *testing.T, currencies []*pb.Currency) {
+				require.Greater(t, len(currencies), 150)
+				for _, currency := range currencies {
+					found, ok := util.LookupCurrency(currency.GetCode())
+					require.True(t, ok)
+					require.Equal(t, int32(found.MinorUnits), currency.GetMinorUnits())
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := util.Config{
+				TokenSymmetricKey: util.RandomString(32),
+				Currencies:        tc.currencies,
+			}
+
+			server, err := NewServer(config, nil)
+			require.NoError(t, err)
+
+			res, err := server.ListCurrencies(context.Background(), &pb.ListCurrenciesRequest{})
+			require.NoError(t, err)
+			tc.checkResponse(t, res.GetCurrencies())
+		})
+	}
+}
+
+func TestNewServerUnknownCurrency(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		Currencies:        []string{util.USD, "XYZ"},
+	}
+
+	server, err := NewServer(config, nil)
+	require.Error(t, err)
+	require.Nil(t, server)
+}
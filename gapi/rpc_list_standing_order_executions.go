@@ -0,0 +1,82 @@
+package gapi
+
+import (
+	"context"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// executionCursor marks the last execution of a page
+type executionCursor struct {
+	ExecutionID int64 `json:"execution_id"`
+}
+
+func (server *Server) ListStandingOrderExecutions(ctx context.Context, req *pb.ListStandingOrderExecutionsRequest) (*pb.ListStandingOrderExecutionsResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor executionCursor
+	if violations := validateListStandingOrderExecutionsRequest(req, &cursor); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if _, err := server.ownStandingOrder(ctx, req.GetStandingOrderId(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	pageSize := req.GetPageSize()
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+
+	executions, err := server.store.ListStandingOrderExecutions(ctx, db.ListStandingOrderExecutionsParams{
+		StandingOrderID: req.GetStandingOrderId(),
+		AfterID:         cursor.ExecutionID,
+		// One extra row tells whether another page follows.
+		PageLimit: pageSize + 1,
+	})
+	if err != nil {
+		return nil, storeError(err, "failed to list standing order executions")
+	}
+
+	rsp := &pb.ListStandingOrderExecutionsResponse{}
+	if len(executions) > int(pageSize) {
+		executions = executions[:pageSize]
+		rsp.NextCursor, err = util.EncodeCursor(executionCursor{ExecutionID: executions[len(executions)-1].ID})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode cursor: %s", err)
+		}
+	}
+	for _, execution := range executions {
+		rsp.Executions = append(rsp.Executions, convertStandingOrderExecution(execution))
+	}
+
+	return rsp, nil
+}
+
+func validateListStandingOrderExecutionsRequest(req *pb.ListStandingOrderExecutionsRequest, cursor *executionCursor) (violations []*errdetails.BadRequest_FieldViolation) {
+	violations = validateStandingOrderID(req.GetStandingOrderId())
+
+	if req.GetCursor() != "" {
+		err := util.DecodeCursor(req.GetCursor(), cursor)
+		if err == nil && cursor.ExecutionID <= 0 {
+			err = util.ErrInvalidCursor
+		}
+		if err != nil {
+			violations = append(violations, fieldViolation("cursor", err))
+		}
+	}
+
+	if err := validatePageSize(req.GetPageSize()); err != nil {
+		violations = append(violations, fieldViolation("page_size", err))
+	}
+
+	return violations
+}
@@ -0,0 +1,118 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestListStandingOrderExecutionsAPI(t *testing.T) {
+	user := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000}
+	order := db.StandingOrder{ID: 7, FromAccountID: account.AccountID, ToAccountID: 2, Amount: 300, Status: db.StandingOrderStatusActive}
+	executions := []db.StandingOrderExecution{
+		{ID: 1, StandingOrderID: order.ID, Status: db.StandingOrderExecutionStatusExecuted, TransactionID: pgtype.Int8{Int64: 11, Valid: true}},
+		{ID: 2, StandingOrderID: order.ID, Status: db.StandingOrderExecutionStatusFailed, FailureReason: pgtype.Text{String: "insufficient funds", Valid: true}},
+		{ID: 3, StandingOrderID: order.ID, Status: db.StandingOrderExecutionStatusSkipped},
+	}
+
+	cursor, err := util.EncodeCursor(executionCursor{ExecutionID: 2})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		req           *pb.ListStandingOrderExecutionsRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ListStandingOrderExecutionsResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      &pb.ListStandingOrderExecutionsRequest{StandingOrderId: order.ID, PageSize: 2},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListStandingOrderExecutionsParams{StandingOrderID: order.ID, AfterID: 0, PageLimit: 3}
+
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListStandingOrderExecutions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(executions, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListStandingOrderExecutionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetExecutions(), 2)
+				require.Equal(t, int64(11), res.GetExecutions()[0].GetTransactionId())
+				require.Equal(t, "insufficient funds", res.GetExecutions()[1].GetFailureReason())
+				require.Equal(t, cursor, res.GetNextCursor())
+			},
+		},
+		{
+			name:     "Next Page",
+			req:      &pb.ListStandingOrderExecutionsRequest{StandingOrderId: order.ID, Cursor: cursor},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListStandingOrderExecutionsParams{StandingOrderID: order.ID, AfterID: 2, PageLimit: defaultPageSize + 1}
+
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListStandingOrderExecutions(gomock.Any(), gomock.Eq(arg)).Times(1).Return(executions[2:], nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListStandingOrderExecutionsResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, res.GetExecutions(), 1)
+				require.Empty(t, res.GetNextCursor())
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.ListStandingOrderExecutionsRequest{StandingOrderId: order.ID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(order, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().ListStandingOrderExecutions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListStandingOrderExecutionsResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Invalid Cursor",
+			req:      &pb.ListStandingOrderExecutionsRequest{StandingOrderId: order.ID, Cursor: "not-a-cursor"},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListStandingOrderExecutions(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ListStandingOrderExecutionsResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.ListStandingOrderExecutions(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxReasonLength = 255
+
+func (server *Server) ReverseTransfer(ctx context.Context, req *pb.ReverseTransferRequest) (*pb.ReverseTransferResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateReverseTransferRequest(req); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	transaction, err := server.store.GetTransaction(ctx, req.GetTransactionId())
+	if err != nil {
+		return nil, storeError(err, "failed to get transaction")
+	}
+
+	if !transaction.DestinationAccountID.Valid {
+		err := fmt.Errorf("%w: transaction %d has multiple legs", db.ErrReversalNotAllowed, transaction.ID)
+		return nil, storeError(err, "failed to reverse transfer")
+	}
+
+	// the refund comes out of the destination account, so only its owner can issue it
+	account, err := server.store.GetAccount(ctx, transaction.DestinationAccountID.Int64)
+	if err != nil {
+		return nil, storeError(err, "failed to get account")
+	}
+
+	if account.Owner != authPayload.Username {
+		return nil, status.Errorf(codes.PermissionDenied, "destination account doesn't belong to the authenticated user")
+	}
+
+	result, err := server.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransactionID: req.GetTransactionId(),
+		Amount:        req.GetAmount(),
+		Reason:        req.GetReason(),
+	})
+	if err != nil {
+		return nil, storeError(err, "failed to reverse transfer")
+	}
+
+	rsp := &pb.ReverseTransferResponse{
+		OriginalTransaction: convertTransaction(result.OriginalTransaction),
+		Reversal:            convertTransferResult(result.Reversal),
+	}
+	return rsp, nil
+}
+
+func validateReverseTransferRequest(req *pb.ReverseTransferRequest) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateID(req.GetTransactionId()); err != nil {
+		violations = append(violations, fieldViolation("transaction_id", err))
+	}
+
+	if req.GetAmount() < 0 {
+		violations = append(violations, fieldViolation("amount", invalidField("min", "must not be negative")))
+	}
+
+	if req.GetReason() == "" {
+		violations = append(violations, fieldViolation("reason", invalidField("required", "is required")))
+	} else if len(req.GetReason()) > maxReasonLength {
+		violations = append(violations, fieldViolation("reason", invalidField("max", "must be at most %d characters", maxReasonLength)))
+	}
+
+	return violations
+}
@@ -0,0 +1,241 @@
+package gapi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReverseTransferAPI(t *testing.T) {
+	user1 := util.RandomOwner()
+	user2 := util.RandomOwner()
+
+	sourceAccount := db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 940}
+	destAccount := db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 560}
+	original := db.Transaction{ID: 5, SourceAccountID: sourceAccount.AccountID, DestinationAccountID: pgtype.Int8{Int64: destAccount.AccountID, Valid: true}, Amount: 60}
+
+	req := &pb.ReverseTransferRequest{TransactionId: original.ID, Amount: 25, Reason: "damaged item"}
+
+	result := db.ReverseTransferTxResult{
+		OriginalTransaction: db.Transaction{ID: 5, SourceAccountID: 1, DestinationAccountID: pgtype.Int8{Int64: 2, Valid: true}, Amount: 60, ReversedAmount: 25},
+		Reversal: db.TransferTxResult{
+			Transaction: db.Transaction{
+				ID:                    6,
+				SourceAccountID:       destAccount.AccountID,
+				DestinationAccountID:  pgtype.Int8{Int64: sourceAccount.AccountID, Valid: true},
+				Amount:                25,
+				OriginalTransactionID: pgtype.Int8{Int64: original.ID, Valid: true},
+				Reason:                pgtype.Text{String: "damaged item", Valid: true},
+			},
+			FromAccount: db.Account{AccountID: 2, Owner: user2, Currency: util.USD, Balance: 535},
+			ToAccount:   db.Account{AccountID: 1, Owner: user1, Currency: util.USD, Balance: 965},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		req           *pb.ReverseTransferRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.ReverseTransferResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ReverseTransferTxParams{TransactionID: original.ID, Amount: 25, Reason: "damaged item"}
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(25), res.GetOriginalTransaction().GetReversedAmount())
+
+				reversal := res.GetReversal().GetTransaction()
+				require.Equal(t, original.ID, reversal.GetOriginalTransactionId())
+				require.Equal(t, "damaged item", reversal.GetReason())
+				require.Equal(t, destAccount.AccountID, reversal.GetSourceAccountId())
+				require.Equal(t, int64(965), res.GetReversal().GetToAccount().GetBalance())
+			},
+		},
+		{
+			name:     "Source Owner",
+			req:      req,
+			username: user1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Reversal Of Reversal",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrReversalNotAllowed)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemReversalNotAllowed), info.GetReason())
+			},
+		},
+		{
+			name:     "Multi-Leg Transaction",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				multiLeg := db.Transaction{ID: original.ID, SourceAccountID: sourceAccount.AccountID, Amount: 60}
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(multiLeg, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemReversalNotAllowed), info.GetReason())
+			},
+		},
+		{
+			name:     "Amount Exceeded",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, db.ErrReversalAmountExceeded)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+
+				st, _ := status.FromError(err)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				require.Equal(t, problemReason(util.ProblemReversalAmountExceeded), info.GetReason())
+			},
+		},
+		{
+			name:     "Amount Too Small To Convert Back",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				tooSmall := fmt.Errorf("%w: refunding 1 gives back no 91 of the converted amount", db.ErrReversalAmountTooSmall)
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, tooSmall)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+
+				st, _ := status.FromError(err)
+				require.Contains(t, st.Message(), "too small to convert back")
+			},
+		},
+		{
+			name:     "Transaction Not Found",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(db.Transaction{}, db.ErrRecordNotFound)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.NotFound)
+			},
+		},
+		{
+			name:     "Missing Reason",
+			req:      &pb.ReverseTransferRequest{TransactionId: original.ID},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Reason Too Long",
+			req:      &pb.ReverseTransferRequest{TransactionId: original.ID, Reason: strings.Repeat("a", maxReasonLength+1)},
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+		{
+			name:     "Internal Error",
+			req:      req,
+			username: user2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransaction(gomock.Any(), gomock.Eq(original.ID)).Times(1).Return(original, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(destAccount.AccountID)).Times(1).Return(destAccount, nil)
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ReverseTransferTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name:     "No Authorization",
+			req:      req,
+			username: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ReverseTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.ReverseTransferResponse, err error) {
+				requireStatusCode(t, err, codes.Unauthenticated)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.ReverseTransfer(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package gapi
+
+import (
+	"context"
+
+	"github.com/chandiniv1/transfers-system/pb"
+)
+
+func (server *Server) VoidHold(ctx context.Context, req *pb.VoidHoldRequest) (*pb.VoidHoldResponse, error) {
+	authPayload, err := server.authorizeUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if violations := validateHoldID(req.GetHoldId()); violations != nil {
+		return nil, invalidArgumentError(violations)
+	}
+
+	if _, err := server.ownHold(ctx, req.GetHoldId(), authPayload.Username); err != nil {
+		return nil, err
+	}
+
+	result, err := server.store.VoidHoldTx(ctx, req.GetHoldId())
+	if err != nil {
+		return nil, storeError(err, "failed to void hold")
+	}
+
+	rsp := &pb.VoidHoldResponse{
+		Hold:    convertHold(result.Hold),
+		Account: convertAccount(result.Account),
+	}
+	return rsp, nil
+}
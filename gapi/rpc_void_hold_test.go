@@ -0,0 +1,106 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	mockdb "github.com/chandiniv1/transfers-system/db/mock"
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestVoidHoldAPI(t *testing.T) {
+	user := util.RandomOwner()
+
+	account := db.Account{AccountID: 1, Owner: user, Currency: util.USD, Balance: 1000, HeldBalance: 300}
+	hold := db.Hold{ID: 7, AccountID: account.AccountID, ToAccountID: 2, Amount: 300, Status: db.HoldStatusActive}
+
+	testCases := []struct {
+		name          string
+		req           *pb.VoidHoldRequest
+		username      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, res *pb.VoidHoldResponse, err error)
+	}{
+		{
+			name:     "OK",
+			req:      &pb.VoidHoldRequest{HoldId: hold.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				voided := hold
+				voided.Status = db.HoldStatusVoided
+				released := account
+				released.HeldBalance = 0
+
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().VoidHoldTx(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(db.HoldTxResult{Hold: voided, Account: released}, nil)
+			},
+			checkResponse: func(t *testing.T, res *pb.VoidHoldResponse, err error) {
+				require.NoError(t, err)
+				require.Equal(t, db.HoldStatusVoided, res.GetHold().GetStatus())
+				require.Equal(t, int64(1000), res.GetAccount().GetAvailableBalance())
+			},
+		},
+		{
+			name:     "Not Active",
+			req:      &pb.VoidHoldRequest{HoldId: hold.ID},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().VoidHoldTx(gomock.Any(), gomock.Any()).Times(1).Return(db.HoldTxResult{}, db.ErrHoldNotActive)
+			},
+			checkResponse: func(t *testing.T, res *pb.VoidHoldResponse, err error) {
+				requireStatusCode(t, err, codes.FailedPrecondition)
+			},
+		},
+		{
+			name:     "Unauthorized User",
+			req:      &pb.VoidHoldRequest{HoldId: hold.ID},
+			username: util.RandomOwner(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(hold, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.AccountID)).Times(1).Return(account, nil)
+				store.EXPECT().VoidHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VoidHoldResponse, err error) {
+				requireStatusCode(t, err, codes.PermissionDenied)
+			},
+		},
+		{
+			name:     "Invalid ID",
+			req:      &pb.VoidHoldRequest{HoldId: 0},
+			username: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().VoidHoldTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, res *pb.VoidHoldResponse, err error) {
+				requireStatusCode(t, err, codes.InvalidArgument)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			ctx := context.Background()
+			if tc.username != "" {
+				ctx = newContextWithBearerToken(t, store, server.tokenMaker, tc.username)
+			}
+
+			res, err := server.VoidHold(ctx, tc.req)
+			tc.checkResponse(t, res, err)
+		})
+	}
+}
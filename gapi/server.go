@@ -0,0 +1,55 @@
+package gapi
+
+import (
+	"fmt"
+
+	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/fx"
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/token"
+	"github.com/chandiniv1/transfers-system/util"
+)
+
+// Server serves gRPC requests for the transfers system
+type Server struct {
+	pb.UnimplementedAccountServiceServer
+	pb.UnimplementedCurrencyServiceServer
+	pb.UnimplementedTransferServiceServer
+	pb.UnimplementedHoldServiceServer
+	pb.UnimplementedScheduledTransferServiceServer
+	pb.UnimplementedStandingOrderServiceServer
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	currencies *util.CurrencyRegistry
+	// rateProvider is nil when conversions are disabled
+	rateProvider fx.RateProvider
+}
+
+// NewServer creates a new gRPC server
+func NewServer(config util.Config, store db.Store) (*Server, error) {
+	tokenMaker, err := token.NewMakerFromConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	currencies, err := util.NewCurrencyRegistry(config.Currencies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create currency registry: %w", err)
+	}
+
+	rateProvider, err := fx.NewProviderFromConfig(config, currencies)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create exchange rate provider: %w", err)
+	}
+
+	server := &Server{
+		config:       config,
+		store:        store,
+		tokenMaker:   tokenMaker,
+		currencies:   currencies,
+		rateProvider: rateProvider,
+	}
+
+	return server, nil
+}
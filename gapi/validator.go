@@ -0,0 +1,113 @@
+package gapi
+
+import (
+	"fmt"
+
+	"github.com/chandiniv1/transfers-system/pb"
+	"github.com/chandiniv1/transfers-system/util"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+const maxPageSize = 100
+
+// invalidFieldCode is the code of a field violation whose value couldn't be parsed at all
+const invalidFieldCode = "invalid"
+
+// fieldError is a field violation with the code clients tell it apart by. Codes are named after
+// the validator tag the HTTP API reports the same rule with, like "required" or "max".
+type fieldError struct {
+	code    string
+	message string
+}
+
+func (e *fieldError) Error() string {
+	return e.message
+}
+
+func invalidField(code, format string, args ...any) error {
+	return &fieldError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func validateID(value int64) error {
+	if value < 1 {
+		return invalidField("min", "must be a positive integer")
+	}
+	return nil
+}
+
+func validateCurrency(currencies *util.CurrencyRegistry, value string) error {
+	if !currencies.IsSupported(value) {
+		return invalidField("currency", "unsupported currency %q", value)
+	}
+	return nil
+}
+
+func validatePageSize(value int32) error {
+	if value < 0 {
+		return invalidField("min", "must be between 1 and %d", maxPageSize)
+	}
+	if value > maxPageSize {
+		return invalidField("max", "must be between 1 and %d", maxPageSize)
+	}
+	return nil
+}
+
+// amountRequest is a request giving an amount either as money or as amount and currency
+type amountRequest interface {
+	GetAmount() int64
+	GetCurrency() string
+	GetMoney() *pb.Money
+}
+
+// requestAmount returns the amount of a request that passed validateAmount
+func requestAmount(req amountRequest) util.Money {
+	if money := req.GetMoney(); money != nil {
+		amount, _ := util.ParseMoney(money.GetAmount(), money.GetCurrency())
+		return amount
+	}
+
+	return util.Money{Amount: req.GetAmount(), Currency: req.GetCurrency()}
+}
+
+// validateAmount checks the amount of a request, which must be positive.
+// field returns the name clients know a field of the request by.
+func validateAmount(req amountRequest, currencies *util.CurrencyRegistry, field func(name string) string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if money := req.GetMoney(); money != nil {
+		if req.GetAmount() != 0 || req.GetCurrency() != "" {
+			violations = append(violations, fieldViolation(field("money"), invalidField("excluded_with", "must not be set along with amount and currency")))
+		}
+		return append(violations, validateMoney(money, currencies, field)...)
+	}
+
+	if req.GetAmount() <= 0 {
+		violations = append(violations, fieldViolation(field("amount"), invalidField("gt", "must be greater than 0")))
+	}
+
+	if err := validateCurrency(currencies, req.GetCurrency()); err != nil {
+		violations = append(violations, fieldViolation(field("currency"), err))
+	}
+
+	return violations
+}
+
+// validateMoney checks that money is a positive amount of a supported currency
+func validateMoney(money *pb.Money, currencies *util.CurrencyRegistry, field func(name string) string) (violations []*errdetails.BadRequest_FieldViolation) {
+	if err := validateCurrency(currencies, money.GetCurrency()); err != nil {
+		return append(violations, fieldViolation(field("money.currency"), err))
+	}
+
+	amount, err := util.ParseMoney(money.GetAmount(), money.GetCurrency())
+	if err != nil {
+		return append(violations, fieldViolation(field("money.amount"), err))
+	}
+	if amount.Amount <= 0 {
+		violations = append(violations, fieldViolation(field("money.amount"), invalidField("gt", "must be greater than 0")))
+	}
+
+	return violations
+}
+
+// requestField names a field of the request itself
+func requestField(name string) string {
+	return name
+}
@@ -2,17 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/chandiniv1/transfers-system/api"
 	db "github.com/chandiniv1/transfers-system/db/sqlc"
+	"github.com/chandiniv1/transfers-system/doc"
+	"github.com/chandiniv1/transfers-system/gapi"
+	"github.com/chandiniv1/transfers-system/pb"
 	"github.com/chandiniv1/transfers-system/util"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
 )
 
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+	syscall.SIGINT,
+}
+
 func main() {
 	config, err := util.LoadConfig(".")
 	if err != nil {
@@ -23,25 +40,265 @@ func main() {
 		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), interruptSignals...)
+	defer stop()
+
 	// ✅ Use pgxpool to connect
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	connPool, err := pgxpool.New(ctx, config.DBSource)
+	connPool, err := pgxpool.New(connectCtx, config.DBSource)
 	if err != nil {
 		log.Fatal().Err(err).Msg("cannot connect to database using pgxpool")
 	}
 
+	txConfig, err := db.NewTxConfig(config)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid transaction config")
+	}
+
 	// ✅ Pass pgx pool to store
-	store := db.NewStore(connPool)
+	store := db.NewStore(connPool, txConfig)
+
+	waitGroup, ctx := errgroup.WithContext(ctx)
+
+	runGrpcServer(ctx, waitGroup, config, store)
+	runGatewayServer(ctx, waitGroup, config, store)
+	runHoldExpirer(ctx, waitGroup, config, store)
+	runScheduledTransferExecutor(ctx, waitGroup, config, store)
+	runStandingOrderExecutor(ctx, waitGroup, config, store)
+	runIdempotencyKeyCleaner(ctx, waitGroup, config, store)
+	runLedgerChecker(ctx, waitGroup, config, store)
 
-	server, err := api.NewServer(store)
+	err = waitGroup.Wait()
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot create server")
+		log.Fatal().Err(err).Msg("error from wait group")
+	}
+}
+
+func runGrpcServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	server, err := gapi.NewServer(config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gRPC server")
+	}
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(gapi.GrpcLogger))
+	pb.RegisterAccountServiceServer(grpcServer, server)
+	pb.RegisterCurrencyServiceServer(grpcServer, server)
+	pb.RegisterTransferServiceServer(grpcServer, server)
+	pb.RegisterHoldServiceServer(grpcServer, server)
+	pb.RegisterScheduledTransferServiceServer(grpcServer, server)
+	pb.RegisterStandingOrderServiceServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	listener, err := net.Listen("tcp", config.GRPCServerAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gRPC listener")
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start gRPC server at %s", listener.Addr().String())
+
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			if errors.Is(err, grpc.ErrServerStopped) {
+				return nil
+			}
+			log.Error().Err(err).Msg("gRPC server failed to serve")
+			return err
+		}
+
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown gRPC server")
+
+		grpcServer.GracefulStop()
+		log.Info().Msg("gRPC server is stopped")
+
+		return nil
+	})
+}
+
+// runGatewayServer serves the HTTP API: the gin routes, the gRPC methods through
+// grpc-gateway for every path gin does not handle, and Swagger UI under /swagger/.
+func runGatewayServer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	grpcServer, err := gapi.NewServer(config, store)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create gRPC server")
+	}
+
+	grpcMux, err := gapi.NewGatewayMux(ctx, grpcServer)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot register gateway handlers")
 	}
 
-	err = server.Start(config.HTTPServerAddr)
+	server, err := api.NewServer(config, store)
 	if err != nil {
-		log.Fatal().Err(err).Msg("cannot start server")
+		log.Fatal().Err(err).Msg("cannot create server")
 	}
+	server.SetNoRouteHandler(grpcMux)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", server.Handler())
+	mux.Handle("/swagger/", http.StripPrefix("/swagger/", doc.SwaggerHandler()))
+
+	httpServer := &http.Server{
+		Addr:    config.HTTPServerAddr,
+		Handler: mux,
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start HTTP gateway server at %s", httpServer.Addr)
+
+		err := httpServer.ListenAndServe()
+		if err != nil {
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+			log.Error().Err(err).Msg("HTTP gateway server failed to serve")
+			return err
+		}
+
+		return nil
+	})
+
+	waitGroup.Go(func() error {
+		<-ctx.Done()
+		log.Info().Msg("graceful shutdown HTTP gateway server")
+
+		err := httpServer.Shutdown(context.Background())
+		if err != nil {
+			log.Error().Err(err).Msg("failed to shutdown HTTP gateway server")
+			return err
+		}
+
+		log.Info().Msg("HTTP gateway server is stopped")
+		return nil
+	})
+}
+
+// runPeriodically runs the background job called name every interval, unless interval is not positive.
+// Each call of run handles at most batchSize items and returns how many it did, so a full batch is
+// followed by another one right away until the backlog is drained.
+func runPeriodically(ctx context.Context, waitGroup *errgroup.Group, name string, interval time.Duration, batchSize int, run func(ctx context.Context) (int, error)) {
+	if interval <= 0 {
+		log.Warn().Msgf("%s is disabled", name)
+		return
+	}
+
+	waitGroup.Go(func() error {
+		log.Info().Msgf("start %s every %s", name, interval)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msgf("%s is stopped", name)
+				return nil
+			case <-ticker.C:
+			}
+
+			for {
+				processed, err := run(ctx)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Error().Err(err).Msgf("%s failed", name)
+					}
+					break
+				}
+				if processed < batchSize {
+					break
+				}
+			}
+		}
+	})
+}
+
+// holdExpiryBatchSize caps how many holds each run of the expirer releases
+const holdExpiryBatchSize = 100
+
+// runHoldExpirer periodically releases the funds of holds that passed their deadline without being captured or voided.
+func runHoldExpirer(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	runPeriodically(ctx, waitGroup, "hold expirer", config.HoldExpiryInterval, holdExpiryBatchSize, func(ctx context.Context) (int, error) {
+		expired, err := store.ExpireHoldsTx(ctx, holdExpiryBatchSize)
+		if expired > 0 {
+			log.Info().Int("expired", expired).Msg("expired holds")
+		}
+		return expired, err
+	})
+}
+
+// scheduledTransferBatchSize caps how many scheduled transfers each run of the executor resolves
+const scheduledTransferBatchSize = 100
+
+// runScheduledTransferExecutor periodically executes the scheduled transfers that are due.
+func runScheduledTransferExecutor(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	runPeriodically(ctx, waitGroup, "scheduled transfer executor", config.ScheduledTransferInterval, scheduledTransferBatchSize, func(ctx context.Context) (int, error) {
+		result, err := store.ExecuteScheduledTransfersTx(ctx, scheduledTransferBatchSize)
+		if result.Executed > 0 || result.Failed > 0 {
+			log.Info().Int("executed", result.Executed).Int("failed", result.Failed).Msg("ran scheduled transfers")
+		}
+		return result.Executed + result.Failed, err
+	})
+}
+
+// standingOrderBatchSize caps how many occurrences of standing orders each run of the executor handles
+const standingOrderBatchSize = 100
+
+// runStandingOrderExecutor periodically runs the occurrences of standing orders that are due.
+func runStandingOrderExecutor(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	arg := db.ExecuteStandingOrdersTxParams{
+		Limit:         standingOrderBatchSize,
+		RetryInterval: config.StandingOrderRetryInterval,
+		MaxRetries:    config.StandingOrderMaxRetries,
+	}
+
+	runPeriodically(ctx, waitGroup, "standing order executor", config.StandingOrderInterval, standingOrderBatchSize, func(ctx context.Context) (int, error) {
+		result, err := store.ExecuteStandingOrdersTx(ctx, arg)
+		if result.Total() > 0 {
+			log.Info().
+				Int("executed", result.Executed).
+				Int("failed", result.Failed).
+				Int("skipped", result.Skipped).
+				Msg("ran standing orders")
+		}
+		return result.Total(), err
+	})
+}
+
+// idempotencyKeyCleanupBatchSize caps how many expired idempotency keys each run of the cleaner deletes
+const idempotencyKeyCleanupBatchSize = 1000
+
+// runIdempotencyKeyCleaner periodically deletes the idempotency keys that have expired.
+func runIdempotencyKeyCleaner(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	runPeriodically(ctx, waitGroup, "idempotency key cleaner", config.IdempotencyKeyCleanupInterval, idempotencyKeyCleanupBatchSize, func(ctx context.Context) (int, error) {
+		deleted, err := store.DeleteExpiredIdempotencyKeys(ctx, idempotencyKeyCleanupBatchSize)
+		if deleted > 0 {
+			log.Info().Int64("deleted", deleted).Msg("deleted expired idempotency keys")
+		}
+		return int(deleted), err
+	})
+}
+
+// runLedgerChecker periodically checks that the balance of every account equals the sum of its entries,
+// logging each account that doesn't. Mismatches are only reported: fixing one needs a person to find
+// out which side is wrong.
+func runLedgerChecker(ctx context.Context, waitGroup *errgroup.Group, config util.Config, store db.Store) {
+	runPeriodically(ctx, waitGroup, "ledger checker", config.LedgerCheckInterval, 1, func(ctx context.Context) (int, error) {
+		mismatches, err := store.ListBalanceMismatches(ctx)
+		for _, mismatch := range mismatches {
+			log.Error().
+				Int64("account_id", mismatch.AccountID).
+				Int64("balance", mismatch.Balance).
+				Int64("entries_total", mismatch.EntriesTotal).
+				Msg("account balance doesn't match its entries")
+		}
+		// the whole ledger is checked at once, so there is never a next batch to run straight away
+		return 0, err
+	})
 }
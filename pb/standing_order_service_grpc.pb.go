@@ -0,0 +1,241 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: standing_order_service.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	StandingOrderService_CreateStandingOrder_FullMethodName         = "/pb.StandingOrderService/CreateStandingOrder"
+	StandingOrderService_GetStandingOrder_FullMethodName            = "/pb.StandingOrderService/GetStandingOrder"
+	StandingOrderService_CancelStandingOrder_FullMethodName         = "/pb.StandingOrderService/CancelStandingOrder"
+	StandingOrderService_ListStandingOrderExecutions_FullMethodName = "/pb.StandingOrderService/ListStandingOrderExecutions"
+)
+
+// StandingOrderServiceClient is the client API for StandingOrderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StandingOrderService repeats a transfer on a schedule. A background job runs the
+// occurrences of active orders once they are due.
+type StandingOrderServiceClient interface {
+	CreateStandingOrder(ctx context.Context, in *CreateStandingOrderRequest, opts ...grpc.CallOption) (*CreateStandingOrderResponse, error)
+	GetStandingOrder(ctx context.Context, in *GetStandingOrderRequest, opts ...grpc.CallOption) (*GetStandingOrderResponse, error)
+	CancelStandingOrder(ctx context.Context, in *CancelStandingOrderRequest, opts ...grpc.CallOption) (*CancelStandingOrderResponse, error)
+	ListStandingOrderExecutions(ctx context.Context, in *ListStandingOrderExecutionsRequest, opts ...grpc.CallOption) (*ListStandingOrderExecutionsResponse, error)
+}
+
+type standingOrderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStandingOrderServiceClient(cc grpc.ClientConnInterface) StandingOrderServiceClient {
+	return &standingOrderServiceClient{cc}
+}
+
+func (c *standingOrderServiceClient) CreateStandingOrder(ctx context.Context, in *CreateStandingOrderRequest, opts ...grpc.CallOption) (*CreateStandingOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateStandingOrderResponse)
+	err := c.cc.Invoke(ctx, StandingOrderService_CreateStandingOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *standingOrderServiceClient) GetStandingOrder(ctx context.Context, in *GetStandingOrderRequest, opts ...grpc.CallOption) (*GetStandingOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStandingOrderResponse)
+	err := c.cc.Invoke(ctx, StandingOrderService_GetStandingOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *standingOrderServiceClient) CancelStandingOrder(ctx context.Context, in *CancelStandingOrderRequest, opts ...grpc.CallOption) (*CancelStandingOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelStandingOrderResponse)
+	err := c.cc.Invoke(ctx, StandingOrderService_CancelStandingOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *standingOrderServiceClient) ListStandingOrderExecutions(ctx context.Context, in *ListStandingOrderExecutionsRequest, opts ...grpc.CallOption) (*ListStandingOrderExecutionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStandingOrderExecutionsResponse)
+	err := c.cc.Invoke(ctx, StandingOrderService_ListStandingOrderExecutions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StandingOrderServiceServer is the server API for StandingOrderService service.
+// All implementations must embed UnimplementedStandingOrderServiceServer
+// for forward compatibility.
+//
+// StandingOrderService repeats a transfer on a schedule. A background job runs the
+// occurrences of active orders once they are due.
+type StandingOrderServiceServer interface {
+	CreateStandingOrder(context.Context, *CreateStandingOrderRequest) (*CreateStandingOrderResponse, error)
+	GetStandingOrder(context.Context, *GetStandingOrderRequest) (*GetStandingOrderResponse, error)
+	CancelStandingOrder(context.Context, *CancelStandingOrderRequest) (*CancelStandingOrderResponse, error)
+	ListStandingOrderExecutions(context.Context, *ListStandingOrderExecutionsRequest) (*ListStandingOrderExecutionsResponse, error)
+	mustEmbedUnimplementedStandingOrderServiceServer()
+}
+
+// UnimplementedStandingOrderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStandingOrderServiceServer struct{}
+
+func (UnimplementedStandingOrderServiceServer) CreateStandingOrder(context.Context, *CreateStandingOrderRequest) (*CreateStandingOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateStandingOrder not implemented")
+}
+func (UnimplementedStandingOrderServiceServer) GetStandingOrder(context.Context, *GetStandingOrderRequest) (*GetStandingOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStandingOrder not implemented")
+}
+func (UnimplementedStandingOrderServiceServer) CancelStandingOrder(context.Context, *CancelStandingOrderRequest) (*CancelStandingOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelStandingOrder not implemented")
+}
+func (UnimplementedStandingOrderServiceServer) ListStandingOrderExecutions(context.Context, *ListStandingOrderExecutionsRequest) (*ListStandingOrderExecutionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStandingOrderExecutions not implemented")
+}
+func (UnimplementedStandingOrderServiceServer) mustEmbedUnimplementedStandingOrderServiceServer() {}
+func (UnimplementedStandingOrderServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeStandingOrderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StandingOrderServiceServer will
+// result in compilation errors.
+type UnsafeStandingOrderServiceServer interface {
+	mustEmbedUnimplementedStandingOrderServiceServer()
+}
+
+func RegisterStandingOrderServiceServer(s grpc.ServiceRegistrar, srv StandingOrderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStandingOrderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StandingOrderService_ServiceDesc, srv)
+}
+
+func _StandingOrderService_CreateStandingOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateStandingOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StandingOrderServiceServer).CreateStandingOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StandingOrderService_CreateStandingOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StandingOrderServiceServer).CreateStandingOrder(ctx, req.(*CreateStandingOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StandingOrderService_GetStandingOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStandingOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StandingOrderServiceServer).GetStandingOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StandingOrderService_GetStandingOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StandingOrderServiceServer).GetStandingOrder(ctx, req.(*GetStandingOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StandingOrderService_CancelStandingOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelStandingOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StandingOrderServiceServer).CancelStandingOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StandingOrderService_CancelStandingOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StandingOrderServiceServer).CancelStandingOrder(ctx, req.(*CancelStandingOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StandingOrderService_ListStandingOrderExecutions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStandingOrderExecutionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StandingOrderServiceServer).ListStandingOrderExecutions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StandingOrderService_ListStandingOrderExecutions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StandingOrderServiceServer).ListStandingOrderExecutions(ctx, req.(*ListStandingOrderExecutionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StandingOrderService_ServiceDesc is the grpc.ServiceDesc for StandingOrderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StandingOrderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.StandingOrderService",
+	HandlerType: (*StandingOrderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateStandingOrder",
+			Handler:    _StandingOrderService_CreateStandingOrder_Handler,
+		},
+		{
+			MethodName: "GetStandingOrder",
+			Handler:    _StandingOrderService_GetStandingOrder_Handler,
+		},
+		{
+			MethodName: "CancelStandingOrder",
+			Handler:    _StandingOrderService_CancelStandingOrder_Handler,
+		},
+		{
+			MethodName: "ListStandingOrderExecutions",
+			Handler:    _StandingOrderService_ListStandingOrderExecutions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "standing_order_service.proto",
+}
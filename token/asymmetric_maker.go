@@ -0,0 +1,145 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	minRSAKeySize = 2048
+	kidHeaderKey  = "kid"
+)
+
+// KeySetProvider is implemented by makers whose tokens can be verified offline with public keys
+type KeySetProvider interface {
+	// JWKS returns the public keys that tokens of the maker can be verified with
+	JWKS() JSONWebKeySet
+}
+
+// AsymmetricJWTMaker signs JWTs with a private key (EdDSA or RS256) and verifies them
+// against a keyring holding the current public key plus any retired ones.
+type AsymmetricJWTMaker struct {
+	signingKey crypto.Signer
+	signingKID string
+	method     jwt.SigningMethod
+	keyring    map[string]crypto.PublicKey
+}
+
+// NewAsymmetricJWTMaker creates a new AsymmetricJWTMaker signing with the given private key.
+// Retired public keys are kept only to verify tokens issued before the last key rotation.
+func NewAsymmetricJWTMaker(signingKey crypto.Signer, retiredKeys ...crypto.PublicKey) (Maker, error) {
+	method, err := signingMethodForKey(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	maker := &AsymmetricJWTMaker{
+		signingKey: signingKey,
+		method:     method,
+		keyring:    make(map[string]crypto.PublicKey),
+	}
+
+	for _, key := range append([]crypto.PublicKey{signingKey.Public()}, retiredKeys...) {
+		if _, err := signingMethodForKey(key); err != nil {
+			return nil, err
+		}
+
+		kid, err := keyID(key)
+		if err != nil {
+			return nil, err
+		}
+		maker.keyring[kid] = key
+	}
+
+	maker.signingKID, err = keyID(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return maker, nil
+}
+
+func (maker *AsymmetricJWTMaker) CreateToken(username string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, sessionID, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	jwtToken := jwt.NewWithClaims(maker.method, payload)
+	jwtToken.Header[kidHeaderKey] = maker.signingKID
+
+	token, err := jwtToken.SignedString(maker.signingKey)
+	return token, payload, err
+}
+
+func (maker *AsymmetricJWTMaker) VerifyToken(token string) (*Payload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header[kidHeaderKey].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		key, ok := maker.keyring[kid]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		method, err := signingMethodForKey(key)
+		if err != nil || method.Alg() != token.Method.Alg() {
+			return nil, ErrInvalidToken
+		}
+
+		return key, nil
+	}
+
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc, jwtParserOptions...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+
+		return nil, ErrInvalidToken
+	}
+
+	payload, ok := jwtToken.Claims.(*Payload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	return payload, nil
+}
+
+// JWKS returns the current and retired public keys of the maker
+func (maker *AsymmetricJWTMaker) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(maker.keyring))}
+
+	// the current key goes first so clients that only look at one key pick the right one
+	set.Keys = append(set.Keys, newJSONWebKey(maker.signingKID, maker.keyring[maker.signingKID]))
+	for kid, key := range maker.keyring {
+		if kid != maker.signingKID {
+			set.Keys = append(set.Keys, newJSONWebKey(kid, key))
+		}
+	}
+
+	return set
+}
+
+func signingMethodForKey(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	case *rsa.PublicKey:
+		if key.Size()*8 < minRSAKeySize {
+			return nil, fmt.Errorf("invalid key size: RSA keys must be at least %d bits", minRSAKeySize)
+		}
+		return jwt.SigningMethodRS256, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
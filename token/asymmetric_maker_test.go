@@ -0,0 +1,209 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newEd25519Key(t *testing.T) crypto.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func newRSAKey(t *testing.T) crypto.Signer {
+	privateKey, err := rsa.GenerateKey(rand.Reader, minRSAKeySize)
+	require.NoError(t, err)
+	return privateKey
+}
+
+func TestAsymmetricJWTMakerEd25519(t *testing.T) {
+	testMaker(t, func(t *testing.T) Maker {
+		maker, err := NewAsymmetricJWTMaker(newEd25519Key(t))
+		require.NoError(t, err)
+		return maker
+	})
+}
+
+func TestAsymmetricJWTMakerRSA(t *testing.T) {
+	testMaker(t, func(t *testing.T) Maker {
+		maker, err := NewAsymmetricJWTMaker(newRSAKey(t))
+		require.NoError(t, err)
+		return maker
+	})
+}
+
+func TestAsymmetricJWTMakerWeakRSAKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	maker, err := NewAsymmetricJWTMaker(privateKey)
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
+
+func TestAsymmetricJWTMakerKeyRotation(t *testing.T) {
+	oldKey := newEd25519Key(t)
+	oldMaker, err := NewAsymmetricJWTMaker(oldKey)
+	require.NoError(t, err)
+
+	token, _, err := oldMaker.CreateToken(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	// after rotation, tokens signed with the retired key are still accepted
+	newMaker, err := NewAsymmetricJWTMaker(newRSAKey(t), oldKey.Public())
+	require.NoError(t, err)
+
+	payload, err := newMaker.VerifyToken(token)
+	require.NoError(t, err)
+	require.NotEmpty(t, payload)
+
+	// once the retired key is dropped from the keyring they are rejected
+	newMaker, err = NewAsymmetricJWTMaker(newRSAKey(t))
+	require.NoError(t, err)
+
+	payload, err = newMaker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestAsymmetricJWTMakerKidHeader(t *testing.T) {
+	signingKey := newEd25519Key(t)
+	maker, err := NewAsymmetricJWTMaker(signingKey)
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Payload{})
+	require.NoError(t, err)
+
+	kid, err := keyID(signingKey.Public())
+	require.NoError(t, err)
+	require.Equal(t, kid, parsed.Header[kidHeaderKey])
+	require.Equal(t, "EdDSA", parsed.Header["alg"])
+}
+
+func TestAsymmetricJWTMakerStandardClaims(t *testing.T) {
+	signingKey := newEd25519Key(t)
+	maker, err := NewAsymmetricJWTMaker(signingKey)
+	require.NoError(t, err)
+
+	// an offline verifier with nothing but the public key and a standard JWT library
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		return signingKey.Public(), nil
+	}
+
+	username := util.RandomOwner()
+	token, payload, err := maker.CreateToken(username, uuid.Nil, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, keyFunc, jwt.WithExpirationRequired(), jwt.WithIssuedAt())
+	require.NoError(t, err)
+	require.Equal(t, payload.ID.String(), claims["jti"])
+	require.Equal(t, payload.SessionID.String(), claims["sid"])
+	require.Equal(t, username, claims["sub"])
+	require.EqualValues(t, payload.IssuedAt.Unix(), claims["iat"])
+	require.EqualValues(t, payload.IssuedAt.Unix(), claims["nbf"])
+	require.EqualValues(t, payload.ExpiredAt.Unix(), claims["exp"])
+
+	expired, _, err := maker.CreateToken(username, uuid.Nil, TokenTypeAccess, -time.Minute)
+	require.NoError(t, err)
+
+	_, err = jwt.ParseWithClaims(expired, jwt.MapClaims{}, keyFunc)
+	require.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestAsymmetricJWTMakerRejectsHMACWithPublicKey(t *testing.T) {
+	signingKey := newEd25519Key(t)
+	maker, err := NewAsymmetricJWTMaker(signingKey)
+	require.NoError(t, err)
+
+	kid, err := keyID(signingKey.Public())
+	require.NoError(t, err)
+
+	payload, err := NewPayload(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+
+	// an attacker signing with HS256 and the public key as secret must not be accepted
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken.Header[kidHeaderKey] = kid
+	token, err := jwtToken.SignedString([]byte(signingKey.Public().(ed25519.PublicKey)))
+	require.NoError(t, err)
+
+	payload, err = maker.VerifyToken(token)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestAsymmetricJWTMakerJWKS(t *testing.T) {
+	currentKey := newEd25519Key(t)
+	retiredKey := newRSAKey(t)
+
+	maker, err := NewAsymmetricJWTMaker(currentKey, retiredKey.Public())
+	require.NoError(t, err)
+
+	provider, ok := maker.(KeySetProvider)
+	require.True(t, ok)
+
+	set := provider.JWKS()
+	require.Len(t, set.Keys, 2)
+
+	currentKID, err := keyID(currentKey.Public())
+	require.NoError(t, err)
+	require.Equal(t, currentKID, set.Keys[0].KeyID)
+	require.Equal(t, "OKP", set.Keys[0].KeyType)
+	require.Equal(t, "Ed25519", set.Keys[0].Curve)
+	require.Equal(t, "EdDSA", set.Keys[0].Algorithm)
+	require.NotEmpty(t, set.Keys[0].X)
+
+	require.Equal(t, "RSA", set.Keys[1].KeyType)
+	require.Equal(t, "RS256", set.Keys[1].Algorithm)
+	require.Equal(t, "AQAB", set.Keys[1].Exponent)
+	require.NotEmpty(t, set.Keys[1].Modulus)
+}
+
+func TestLoadKeys(t *testing.T) {
+	signingKey := newEd25519Key(t)
+	dir := t.TempDir()
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(signingKey)
+	require.NoError(t, err)
+	privatePath := filepath.Join(dir, "private.pem")
+	err = os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0600)
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(signingKey.Public())
+	require.NoError(t, err)
+	publicPath := filepath.Join(dir, "public.pem")
+	err = os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0600)
+	require.NoError(t, err)
+
+	loadedPrivate, err := LoadPrivateKey(privatePath)
+	require.NoError(t, err)
+	require.Equal(t, signingKey, loadedPrivate)
+
+	loadedPublic, err := LoadPublicKey(publicPath)
+	require.NoError(t, err)
+	require.Equal(t, signingKey.Public(), loadedPublic)
+
+	_, err = LoadPrivateKey(publicPath)
+	require.Error(t, err)
+
+	_, err = LoadPublicKey(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+}
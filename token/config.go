@@ -0,0 +1,30 @@
+package token
+
+import (
+	"crypto"
+
+	"github.com/chandiniv1/transfers-system/util"
+)
+
+// NewMakerFromConfig builds the token maker selected by the config
+func NewMakerFromConfig(config util.Config) (Maker, error) {
+	if config.TokenType != TypeAsymmetricJWT {
+		return NewMaker(config.TokenType, config.TokenSymmetricKey)
+	}
+
+	signingKey, err := LoadPrivateKey(config.TokenPrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	retiredKeys := make([]crypto.PublicKey, 0, len(config.TokenRetiredKeyPaths))
+	for _, path := range config.TokenRetiredKeyPaths {
+		key, err := LoadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		retiredKeys = append(retiredKeys, key)
+	}
+
+	return NewAsymmetricJWTMaker(signingKey, retiredKeys...)
+}
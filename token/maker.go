@@ -1,11 +1,36 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Supported token maker types
+const (
+	TypeJWT           = "jwt"
+	TypePaseto        = "paseto"
+	TypeAsymmetricJWT = "jwt_asymmetric"
+)
 
 type Maker interface {
-	// CreateToken creates a new token for a specific username and duration
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a new token of the given type for a specific username, session and duration.
+	// A nil session ID makes the token start a new session keyed by its own ID.
+	CreateToken(username string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error)
 
 	// VerifyToken checks if the token is valid or not
 	VerifyToken(token string) (*Payload, error)
 }
+
+// NewMaker creates a symmetric token maker of the given type, defaulting to JWT.
+// Asymmetric makers need key material and are built with NewAsymmetricJWTMaker instead.
+func NewMaker(tokenType string, symmetricKey string) (Maker, error) {
+	switch tokenType {
+	case "", TypeJWT:
+		return NewJWTMaker(symmetricKey)
+	case TypePaseto:
+		return NewPasetoMaker(symmetricKey)
+	}
+	return nil, fmt.Errorf("unsupported token type %q", tokenType)
+}
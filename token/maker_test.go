@@ -0,0 +1,144 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// testMaker runs the conformance suite that every Maker implementation must pass.
+func testMaker(t *testing.T, newMaker func(t *testing.T) Maker) {
+	t.Run("Valid Token", func(t *testing.T) {
+		maker := newMaker(t)
+
+		username := util.RandomOwner()
+		duration := time.Minute
+
+		issuedAt := time.Now()
+		expiredAt := issuedAt.Add(duration)
+
+		token, payload, err := maker.CreateToken(username, uuid.Nil, TokenTypeAccess, duration)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.NotEmpty(t, payload)
+
+		payload, err = maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.NotEmpty(t, payload)
+
+		require.NotZero(t, payload.ID)
+		require.Equal(t, payload.ID, payload.SessionID)
+		require.Equal(t, username, payload.Username)
+		require.Equal(t, TokenTypeAccess, payload.Type)
+		require.NoError(t, payload.CheckType(TokenTypeAccess))
+		require.ErrorIs(t, payload.CheckType(TokenTypeRefresh), ErrInvalidToken)
+		require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
+		require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
+	})
+
+	t.Run("Session Token", func(t *testing.T) {
+		maker := newMaker(t)
+		sessionID := uuid.New()
+
+		token, _, err := maker.CreateToken(util.RandomOwner(), sessionID, TokenTypeAccess, time.Minute)
+		require.NoError(t, err)
+
+		payload, err := maker.VerifyToken(token)
+		require.NoError(t, err)
+		require.Equal(t, sessionID, payload.SessionID)
+		require.NotEqual(t, sessionID, payload.ID)
+	})
+
+	t.Run("Expired Token", func(t *testing.T) {
+		maker := newMaker(t)
+
+		token, payload, err := maker.CreateToken(util.RandomOwner(), uuid.Nil, TokenTypeAccess, -time.Minute)
+		require.NoError(t, err)
+		require.NotEmpty(t, token)
+		require.NotEmpty(t, payload)
+
+		payload, err = maker.VerifyToken(token)
+		require.Error(t, err)
+		require.EqualError(t, err, ErrExpiredToken.Error())
+		require.Nil(t, payload)
+	})
+
+	t.Run("Tampered Token", func(t *testing.T) {
+		maker := newMaker(t)
+
+		token, _, err := maker.CreateToken(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+		require.NoError(t, err)
+
+		tampered := []byte(token)
+		i := len(tampered) / 2
+		if tampered[i] == 'A' {
+			tampered[i] = 'B'
+		} else {
+			tampered[i] = 'A'
+		}
+
+		payload, err := maker.VerifyToken(string(tampered))
+		require.Error(t, err)
+		require.EqualError(t, err, ErrInvalidToken.Error())
+		require.Nil(t, payload)
+	})
+
+	t.Run("Wrong Key", func(t *testing.T) {
+		token, _, err := newMaker(t).CreateToken(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+		require.NoError(t, err)
+
+		payload, err := newMaker(t).VerifyToken(token)
+		require.Error(t, err)
+		require.EqualError(t, err, ErrInvalidToken.Error())
+		require.Nil(t, payload)
+	})
+
+	t.Run("Malformed Token", func(t *testing.T) {
+		maker := newMaker(t)
+
+		payload, err := maker.VerifyToken("not-a-token")
+		require.Error(t, err)
+		require.EqualError(t, err, ErrInvalidToken.Error())
+		require.Nil(t, payload)
+	})
+}
+
+func TestNewMaker(t *testing.T) {
+	key := util.RandomString(32)
+
+	maker, err := NewMaker("", key)
+	require.NoError(t, err)
+	require.IsType(t, &JWTMaker{}, maker)
+
+	maker, err = NewMaker(TypeJWT, key)
+	require.NoError(t, err)
+	require.IsType(t, &JWTMaker{}, maker)
+
+	maker, err = NewMaker(TypePaseto, key)
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	_, err = NewMaker("unknown", key)
+	require.Error(t, err)
+}
+
+func TestPayloadValid(t *testing.T) {
+	payload, err := NewPayload(util.RandomOwner(), uuid.Nil, TokenTypeAccess, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, payload.Valid())
+
+	payload.IssuedAt = time.Now().Add(time.Minute)
+	require.ErrorIs(t, payload.Valid(), ErrInvalidToken)
+
+	payload.IssuedAt, payload.ExpiredAt = time.Now().Add(-time.Minute), time.Now()
+	require.ErrorIs(t, payload.Valid(), ErrExpiredToken)
+}
+
+func TestPayloadRequiresTimeClaims(t *testing.T) {
+	var payload Payload
+	err := payload.UnmarshalJSON([]byte(`{"jti": "` + uuid.NewString() + `", "sid": "` + uuid.NewString() + `", "sub": "alice", "iat": 1700000000}`))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
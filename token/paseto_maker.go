@@ -0,0 +1,56 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasetoMaker is a PASETO v4.local token maker
+type PasetoMaker struct {
+	symmetricKey []byte
+}
+
+// NewPasetoMaker creates a new PasetoMaker
+func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	if len(symmetricKey) != v4SymmetricSize {
+		return nil, fmt.Errorf("invalid key size: must be exactly %d characters", v4SymmetricSize)
+	}
+
+	return &PasetoMaker{symmetricKey: []byte(symmetricKey)}, nil
+}
+
+func (maker *PasetoMaker) CreateToken(username string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(username, sessionID, tokenType, duration)
+	if err != nil {
+		return "", payload, err
+	}
+
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := v4LocalEncrypt(maker.symmetricKey, message, nil, nil)
+	return token, payload, err
+}
+
+func (maker *PasetoMaker) VerifyToken(token string) (*Payload, error) {
+	message, _, err := v4LocalDecrypt(maker.symmetricKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.Valid(); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
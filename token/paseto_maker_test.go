@@ -0,0 +1,62 @@
+package token
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/chandiniv1/transfers-system/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPasetoMaker(t *testing.T) Maker {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+	return maker
+}
+
+func TestPasetoMaker(t *testing.T) {
+	testMaker(t, newTestPasetoMaker)
+}
+
+func TestPasetoMakerInvalidKeySize(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(33))
+	require.Error(t, err)
+	require.Nil(t, maker)
+}
+
+// TestV4LocalVector checks the implementation against test vector 4-E-1 of the PASETO specification.
+func TestV4LocalVector(t *testing.T) {
+	key, err := hex.DecodeString("707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f")
+	require.NoError(t, err)
+
+	nonce := make([]byte, v4NonceSize)
+	message := []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+	expected := "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg"
+
+	token, err := v4LocalEncryptWithNonce(key, nonce, message, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, expected, token)
+
+	decrypted, footer, err := v4LocalDecrypt(key, token, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+	require.Empty(t, footer)
+}
+
+func TestV4LocalFooterAndImplicit(t *testing.T) {
+	key := []byte(util.RandomString(32))
+	message := []byte("message")
+	footer := []byte("footer")
+	implicit := []byte("implicit")
+
+	token, err := v4LocalEncrypt(key, message, footer, implicit)
+	require.NoError(t, err)
+
+	decrypted, gotFooter, err := v4LocalDecrypt(key, token, implicit)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+	require.Equal(t, footer, gotFooter)
+
+	_, _, err = v4LocalDecrypt(key, token, []byte("other"))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,167 @@
+package token
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// This file implements the v4.local protocol of the PASETO specification:
+// https://github.com/paseto-standard/paseto-spec/blob/master/docs/01-Protocol-Versions/Version4.md
+
+const (
+	v4LocalHeader   = "v4.local."
+	v4NonceSize     = 32
+	v4MacSize       = 32
+	v4EncKeyInfo    = "paseto-encryption-key"
+	v4AuthKeyInfo   = "paseto-auth-key-for-aead"
+	v4SymmetricSize = 32
+)
+
+var pasetoEncoding = base64.RawURLEncoding
+
+// v4LocalEncrypt encrypts the message with the given key and a random nonce.
+func v4LocalEncrypt(key, message, footer, implicit []byte) (string, error) {
+	nonce := make([]byte, v4NonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return v4LocalEncryptWithNonce(key, nonce, message, footer, implicit)
+}
+
+func v4LocalEncryptWithNonce(key, nonce, message, footer, implicit []byte) (string, error) {
+	encKey, counterNonce, authKey, err := v4SplitKey(key, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return "", err
+	}
+	ciphertext := make([]byte, len(message))
+	cipher.XORKeyStream(ciphertext, message)
+
+	tag, err := v4Mac(authKey, nonce, ciphertext, footer, implicit)
+	if err != nil {
+		return "", err
+	}
+
+	body := make([]byte, 0, len(nonce)+len(ciphertext)+len(tag))
+	body = append(body, nonce...)
+	body = append(body, ciphertext...)
+	body = append(body, tag...)
+
+	token := v4LocalHeader + pasetoEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + pasetoEncoding.EncodeToString(footer)
+	}
+
+	return token, nil
+}
+
+// v4LocalDecrypt authenticates and decrypts the token, returning the message and footer.
+func v4LocalDecrypt(key []byte, token string, implicit []byte) ([]byte, []byte, error) {
+	if !strings.HasPrefix(token, v4LocalHeader) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	parts := strings.Split(strings.TrimPrefix(token, v4LocalHeader), ".")
+	if len(parts) > 2 {
+		return nil, nil, ErrInvalidToken
+	}
+
+	body, err := pasetoEncoding.DecodeString(parts[0])
+	if err != nil || len(body) < v4NonceSize+v4MacSize {
+		return nil, nil, ErrInvalidToken
+	}
+
+	var footer []byte
+	if len(parts) == 2 {
+		footer, err = pasetoEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, ErrInvalidToken
+		}
+	}
+
+	nonce := body[:v4NonceSize]
+	ciphertext := body[v4NonceSize : len(body)-v4MacSize]
+	tag := body[len(body)-v4MacSize:]
+
+	encKey, counterNonce, authKey, err := v4SplitKey(key, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expectedTag, err := v4Mac(authKey, nonce, ciphertext, footer, implicit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if subtle.ConstantTimeCompare(tag, expectedTag) != 1 {
+		return nil, nil, ErrInvalidToken
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(encKey, counterNonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	message := make([]byte, len(ciphertext))
+	cipher.XORKeyStream(message, ciphertext)
+
+	return message, footer, nil
+}
+
+// v4SplitKey derives the encryption key, the XChaCha20 nonce and the authentication key.
+func v4SplitKey(key, nonce []byte) ([]byte, []byte, []byte, error) {
+	tmp, err := blake2bSum(key, 56, []byte(v4EncKeyInfo), nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	authKey, err := blake2bSum(key, 32, []byte(v4AuthKeyInfo), nonce)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return tmp[:32], tmp[32:], authKey, nil
+}
+
+func v4Mac(authKey, nonce, ciphertext, footer, implicit []byte) ([]byte, error) {
+	preAuth := preAuthEncode([]byte(v4LocalHeader), nonce, ciphertext, footer, implicit)
+	return blake2bSum(authKey, v4MacSize, preAuth)
+}
+
+func blake2bSum(key []byte, size int, data ...[]byte) ([]byte, error) {
+	hash, err := blake2b.New(size, key)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range data {
+		hash.Write(d)
+	}
+	return hash.Sum(nil), nil
+}
+
+// preAuthEncode implements PAE, the pre-authentication encoding of PASETO.
+func preAuthEncode(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(le64(uint64(len(pieces))))
+	for _, piece := range pieces {
+		buf.Write(le64(uint64(len(piece))))
+		buf.Write(piece)
+	}
+	return buf.Bytes()
+}
+
+func le64(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, n&^(1<<63))
+	return b
+}
@@ -1,7 +1,9 @@
 package token
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,22 +15,42 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// TokenType tells what a token was issued for, so a refresh token can't be used as an access token or the other way round
+type TokenType string
+
+const (
+	TokenTypeAccess  TokenType = "access"
+	TokenTypeRefresh TokenType = "refresh"
+)
+
+// Payload is what a token says about the user it was issued to.
+// It is encoded with the registered claims of RFC 7519, so verifiers using the JWKS with a standard
+// JWT library check its expiry: jti is the token ID, sub the username, iat and nbf the issue time and
+// exp the expiry. The session ID goes in sid.
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
+	SessionID uuid.UUID `json:"session_id"`
 	Username  string    `json:"username"`
+	Type      TokenType `json:"token_type"`
 	IssuedAt  time.Time `json:"issued_at"`
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
-func NewPayload(userName string, duration time.Duration) (*Payload, error) {
+func NewPayload(userName string, sessionID uuid.UUID, tokenType TokenType, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
+	if sessionID == uuid.Nil {
+		sessionID = tokenID
+	}
+
 	payload := &Payload{
 		ID:        tokenID,
+		SessionID: sessionID,
 		Username:  userName,
+		Type:      tokenType,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(duration),
 	}
@@ -36,20 +58,64 @@ func NewPayload(userName string, duration time.Duration) (*Payload, error) {
 	return payload, nil
 }
 
+// claims is the encoding of a Payload in tokens
+type claims struct {
+	jwt.RegisteredClaims
+	SessionID uuid.UUID `json:"sid"`
+	Type      TokenType `json:"token_type"`
+}
+
+func (payload *Payload) MarshalJSON() ([]byte, error) {
+	return json.Marshal(claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        payload.ID.String(),
+			Subject:   payload.Username,
+			IssuedAt:  jwt.NewNumericDate(payload.IssuedAt),
+			NotBefore: jwt.NewNumericDate(payload.IssuedAt),
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiredAt),
+		},
+		SessionID: payload.SessionID,
+		Type:      payload.Type,
+	})
+}
+
+// UnmarshalJSON decodes the claims of a token, which must all be present
+func (payload *Payload) UnmarshalJSON(data []byte) error {
+	var c claims
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+
+	if c.IssuedAt == nil || c.NotBefore == nil || c.ExpiresAt == nil || c.SessionID == uuid.Nil {
+		return ErrInvalidToken
+	}
+
+	tokenID, err := uuid.Parse(c.ID)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	*payload = Payload{
+		ID:        tokenID,
+		SessionID: c.SessionID,
+		Username:  c.Subject,
+		Type:      c.Type,
+		IssuedAt:  c.IssuedAt.Time,
+		ExpiredAt: c.ExpiresAt.Time,
+	}
+	return nil
+}
+
 func (payload *Payload) GetAudience() (jwt.ClaimStrings, error) {
-	return jwt.ClaimStrings{}, nil
+	return nil, nil
 }
 
 func (payload *Payload) GetExpirationTime() (*jwt.NumericDate, error) {
-	return &jwt.NumericDate{
-		Time: payload.ExpiredAt,
-	}, nil
+	return jwt.NewNumericDate(payload.ExpiredAt), nil
 }
 
 func (payload *Payload) GetIssuedAt() (*jwt.NumericDate, error) {
-	return &jwt.NumericDate{
-		Time: payload.IssuedAt,
-	}, nil
+	return jwt.NewNumericDate(payload.IssuedAt), nil
 }
 
 func (payload *Payload) GetIssuer() (string, error) {
@@ -57,18 +123,30 @@ func (payload *Payload) GetIssuer() (string, error) {
 }
 
 func (payload *Payload) GetNotBefore() (*jwt.NumericDate, error) {
-	return &jwt.NumericDate{
-		Time: payload.IssuedAt,
-	}, nil
+	return jwt.NewNumericDate(payload.IssuedAt), nil
 }
 
 func (payload *Payload) GetSubject() (string, error) {
-	return "", nil
+	return payload.Username, nil
 }
 
+// Valid checks the time claims of a token whose format doesn't, like PASETO:
+// it must be issued and valid already, and not be expired
 func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
+	now := time.Now()
+	if now.Before(payload.IssuedAt.Truncate(time.Second)) {
+		return ErrInvalidToken
+	}
+	if !now.Before(payload.ExpiredAt) {
 		return ErrExpiredToken
 	}
 	return nil
 }
+
+// CheckType returns ErrInvalidToken unless the token was issued as tokenType
+func (payload *Payload) CheckType(tokenType TokenType) error {
+	if payload.Type != tokenType {
+		return fmt.Errorf("%w: %s token required", ErrInvalidToken, tokenType)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -11,6 +13,38 @@ type Config struct {
 	Environment    string `mapstructure:"ENVIRONMENT"`
 	HTTPServerAddr string `mapstructure:"HTTP_SERVER_ADDRESS"`
 	GRPCServerAddr string `mapstructure:"GRPC_SERVER_ADDRESS"`
+
+	TokenType            string        `mapstructure:"TOKEN_TYPE"`
+	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenPrivateKeyPath  string        `mapstructure:"TOKEN_PRIVATE_KEY_PATH"`
+	TokenRetiredKeyPaths []string      `mapstructure:"TOKEN_RETIRED_KEY_PATHS"`
+	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+
+	IdempotencyKeyTTL             time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	IdempotencyKeyCleanupInterval time.Duration `mapstructure:"IDEMPOTENCY_KEY_CLEANUP_INTERVAL"`
+
+	TransferIsolationLevel string        `mapstructure:"TRANSFER_ISOLATION_LEVEL"`
+	TxMaxRetries           int           `mapstructure:"TX_MAX_RETRIES"`
+	TxRetryBaseDelay       time.Duration `mapstructure:"TX_RETRY_BASE_DELAY"`
+	TxRetryMaxDelay        time.Duration `mapstructure:"TX_RETRY_MAX_DELAY"`
+
+	HoldDuration       time.Duration `mapstructure:"HOLD_DURATION"`
+	HoldExpiryInterval time.Duration `mapstructure:"HOLD_EXPIRY_INTERVAL"`
+
+	ScheduledTransferInterval time.Duration `mapstructure:"SCHEDULED_TRANSFER_INTERVAL"`
+
+	StandingOrderInterval      time.Duration `mapstructure:"STANDING_ORDER_INTERVAL"`
+	StandingOrderRetryInterval time.Duration `mapstructure:"STANDING_ORDER_RETRY_INTERVAL"`
+	StandingOrderMaxRetries    int32         `mapstructure:"STANDING_ORDER_MAX_RETRIES"`
+
+	LedgerCheckInterval time.Duration `mapstructure:"LEDGER_CHECK_INTERVAL"`
+
+	Currencies            []string `mapstructure:"CURRENCIES"`
+	OneAccountPerCurrency bool     `mapstructure:"ONE_ACCOUNT_PER_CURRENCY"`
+
+	FXRatesPath string `mapstructure:"FX_RATES_PATH"`
+	FXSpreadBps int64  `mapstructure:"FX_SPREAD_BPS"`
 }
 
 // LoadConfig reads configuration from file or environment variable
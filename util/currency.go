@@ -1,15 +1,252 @@
 package util
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Codes of the currencies enabled out of the box
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
-func IsSupportedCurrency(currency string) bool {
-	switch currency {
-	case USD, EUR, CAD:
-		return true
+// Currency is an ISO 4217 currency
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode string `json:"numeric_code"`
+	Name        string `json:"name"`
+	// MinorUnits is the number of decimal places of the minor unit, 2 for cents and 0 for the yen
+	MinorUnits int `json:"minor_units"`
+}
+
+// iso4217 lists the active ISO 4217 currencies, leaving out funds, precious metals and testing codes
+var iso4217 = []Currency{
+	{Code: "AED", NumericCode: "784", Name: "UAE Dirham", MinorUnits: 2},
+	{Code: "AFN", NumericCode: "971", Name: "Afghani", MinorUnits: 2},
+	{Code: "ALL", NumericCode: "008", Name: "Lek", MinorUnits: 2},
+	{Code: "AMD", NumericCode: "051", Name: "Armenian Dram", MinorUnits: 2},
+	{Code: "AOA", NumericCode: "973", Name: "Kwanza", MinorUnits: 2},
+	{Code: "ARS", NumericCode: "032", Name: "Argentine Peso", MinorUnits: 2},
+	{Code: "AUD", NumericCode: "036", Name: "Australian Dollar", MinorUnits: 2},
+	{Code: "AWG", NumericCode: "533", Name: "Aruban Florin", MinorUnits: 2},
+	{Code: "AZN", NumericCode: "944", Name: "Azerbaijan Manat", MinorUnits: 2},
+	{Code: "BAM", NumericCode: "977", Name: "Convertible Mark", MinorUnits: 2},
+	{Code: "BBD", NumericCode: "052", Name: "Barbados Dollar", MinorUnits: 2},
+	{Code: "BDT", NumericCode: "050", Name: "Taka", MinorUnits: 2},
+	{Code: "BHD", NumericCode: "048", Name: "Bahraini Dinar", MinorUnits: 3},
+	{Code: "BIF", NumericCode: "108", Name: "Burundi Franc", MinorUnits: 0},
+	{Code: "BMD", NumericCode: "060", Name: "Bermudian Dollar", MinorUnits: 2},
+	{Code: "BND", NumericCode: "096", Name: "Brunei Dollar", MinorUnits: 2},
+	{Code: "BOB", NumericCode: "068", Name: "Boliviano", MinorUnits: 2},
+	{Code: "BRL", NumericCode: "986", Name: "Brazilian Real", MinorUnits: 2},
+	{Code: "BSD", NumericCode: "044", Name: "Bahamian Dollar", MinorUnits: 2},
+	{Code: "BTN", NumericCode: "064", Name: "Ngultrum", MinorUnits: 2},
+	{Code: "BWP", NumericCode: "072", Name: "Pula", MinorUnits: 2},
+	{Code: "BYN", NumericCode: "933", Name: "Belarusian Ruble", MinorUnits: 2},
+	{Code: "BZD", NumericCode: "084", Name: "Belize Dollar", MinorUnits: 2},
+	{Code: "CAD", NumericCode: "124", Name: "Canadian Dollar", MinorUnits: 2},
+	{Code: "CDF", NumericCode: "976", Name: "Congolese Franc", MinorUnits: 2},
+	{Code: "CHF", NumericCode: "756", Name: "Swiss Franc", MinorUnits: 2},
+	{Code: "CLP", NumericCode: "152", Name: "Chilean Peso", MinorUnits: 0},
+	{Code: "CNY", NumericCode: "156", Name: "Yuan Renminbi", MinorUnits: 2},
+	{Code: "COP", NumericCode: "170", Name: "Colombian Peso", MinorUnits: 2},
+	{Code: "CRC", NumericCode: "188", Name: "Costa Rican Colon", MinorUnits: 2},
+	{Code: "CUP", NumericCode: "192", Name: "Cuban Peso", MinorUnits: 2},
+	{Code: "CVE", NumericCode: "132", Name: "Cabo Verde Escudo", MinorUnits: 2},
+	{Code: "CZK", NumericCode: "203", Name: "Czech Koruna", MinorUnits: 2},
+	{Code: "DJF", NumericCode: "262", Name: "Djibouti Franc", MinorUnits: 0},
+	{Code: "DKK", NumericCode: "208", Name: "Danish Krone", MinorUnits: 2},
+	{Code: "DOP", NumericCode: "214", Name: "Dominican Peso", MinorUnits: 2},
+	{Code: "DZD", NumericCode: "012", Name: "Algerian Dinar", MinorUnits: 2},
+	{Code: "EGP", NumericCode: "818", Name: "Egyptian Pound", MinorUnits: 2},
+	{Code: "ERN", NumericCode: "232", Name: "Nakfa", MinorUnits: 2},
+	{Code: "ETB", NumericCode: "230", Name: "Ethiopian Birr", MinorUnits: 2},
+	{Code: "EUR", NumericCode: "978", Name: "Euro", MinorUnits: 2},
+	{Code: "FJD", NumericCode: "242", Name: "Fiji Dollar", MinorUnits: 2},
+	{Code: "FKP", NumericCode: "238", Name: "Falkland Islands Pound", MinorUnits: 2},
+	{Code: "GBP", NumericCode: "826", Name: "Pound Sterling", MinorUnits: 2},
+	{Code: "GEL", NumericCode: "981", Name: "Lari", MinorUnits: 2},
+	{Code: "GHS", NumericCode: "936", Name: "Ghana Cedi", MinorUnits: 2},
+	{Code: "GIP", NumericCode: "292", Name: "Gibraltar Pound", MinorUnits: 2},
+	{Code: "GMD", NumericCode: "270", Name: "Dalasi", MinorUnits: 2},
+	{Code: "GNF", NumericCode: "324", Name: "Guinean Franc", MinorUnits: 0},
+	{Code: "GTQ", NumericCode: "320", Name: "Quetzal", MinorUnits: 2},
+	{Code: "GYD", NumericCode: "328", Name: "Guyana Dollar", MinorUnits: 2},
+	{Code: "HKD", NumericCode: "344", Name: "Hong Kong Dollar", MinorUnits: 2},
+	{Code: "HNL", NumericCode: "340", Name: "Lempira", MinorUnits: 2},
+	{Code: "HTG", NumericCode: "332", Name: "Gourde", MinorUnits: 2},
+	{Code: "HUF", NumericCode: "348", Name: "Forint", MinorUnits: 2},
+	{Code: "IDR", NumericCode: "360", Name: "Rupiah", MinorUnits: 2},
+	{Code: "ILS", NumericCode: "376", Name: "New Israeli Sheqel", MinorUnits: 2},
+	{Code: "INR", NumericCode: "356", Name: "Indian Rupee", MinorUnits: 2},
+	{Code: "IQD", NumericCode: "368", Name: "Iraqi Dinar", MinorUnits: 3},
+	{Code: "IRR", NumericCode: "364", Name: "Iranian Rial", MinorUnits: 2},
+	{Code: "ISK", NumericCode: "352", Name: "Iceland Krona", MinorUnits: 0},
+	{Code: "JMD", NumericCode: "388", Name: "Jamaican Dollar", MinorUnits: 2},
+	{Code: "JOD", NumericCode: "400", Name: "Jordanian Dinar", MinorUnits: 3},
+	{Code: "JPY", NumericCode: "392", Name: "Yen", MinorUnits: 0},
+	{Code: "KES", NumericCode: "404", Name: "Kenyan Shilling", MinorUnits: 2},
+	{Code: "KGS", NumericCode: "417", Name: "Som", MinorUnits: 2},
+	{Code: "KHR", NumericCode: "116", Name: "Riel", MinorUnits: 2},
+	{Code: "KMF", NumericCode: "174", Name: "Comorian Franc", MinorUnits: 0},
+	{Code: "KPW", NumericCode: "408", Name: "North Korean Won", MinorUnits: 2},
+	{Code: "KRW", NumericCode: "410", Name: "Won", MinorUnits: 0},
+	{Code: "KWD", NumericCode: "414", Name: "Kuwaiti Dinar", MinorUnits: 3},
+	{Code: "KYD", NumericCode: "136", Name: "Cayman Islands Dollar", MinorUnits: 2},
+	{Code: "KZT", NumericCode: "398", Name: "Tenge", MinorUnits: 2},
+	{Code: "LAK", NumericCode: "418", Name: "Lao Kip", MinorUnits: 2},
+	{Code: "LBP", NumericCode: "422", Name: "Lebanese Pound", MinorUnits: 2},
+	{Code: "LKR", NumericCode: "144", Name: "Sri Lanka Rupee", MinorUnits: 2},
+	{Code: "LRD", NumericCode: "430", Name: "Liberian Dollar", MinorUnits: 2},
+	{Code: "LSL", NumericCode: "426", Name: "Loti", MinorUnits: 2},
+	{Code: "LYD", NumericCode: "434", Name: "Libyan Dinar", MinorUnits: 3},
+	{Code: "MAD", NumericCode: "504", Name: "Moroccan Dirham", MinorUnits: 2},
+	{Code: "MDL", NumericCode: "498", Name: "Moldovan Leu", MinorUnits: 2},
+	{Code: "MGA", NumericCode: "969", Name: "Malagasy Ariary", MinorUnits: 2},
+	{Code: "MKD", NumericCode: "807", Name: "Denar", MinorUnits: 2},
+	{Code: "MMK", NumericCode: "104", Name: "Kyat", MinorUnits: 2},
+	{Code: "MNT", NumericCode: "496", Name: "Tugrik", MinorUnits: 2},
+	{Code: "MOP", NumericCode: "446", Name: "Pataca", MinorUnits: 2},
+	{Code: "MRU", NumericCode: "929", Name: "Ouguiya", MinorUnits: 2},
+	{Code: "MUR", NumericCode: "480", Name: "Mauritius Rupee", MinorUnits: 2},
+	{Code: "MVR", NumericCode: "462", Name: "Rufiyaa", MinorUnits: 2},
+	{Code: "MWK", NumericCode: "454", Name: "Malawi Kwacha", MinorUnits: 2},
+	{Code: "MXN", NumericCode: "484", Name: "Mexican Peso", MinorUnits: 2},
+	{Code: "MYR", NumericCode: "458", Name: "Malaysian Ringgit", MinorUnits: 2},
+	{Code: "MZN", NumericCode: "943", Name: "Mozambique Metical", MinorUnits: 2},
+	{Code: "NAD", NumericCode: "516", Name: "Namibia Dollar", MinorUnits: 2},
+	{Code: "NGN", NumericCode: "566", Name: "Naira", MinorUnits: 2},
+	{Code: "NIO", NumericCode: "558", Name: "Cordoba Oro", MinorUnits: 2},
+	{Code: "NOK", NumericCode: "578", Name: "Norwegian Krone", MinorUnits: 2},
+	{Code: "NPR", NumericCode: "524", Name: "Nepalese Rupee", MinorUnits: 2},
+	{Code: "NZD", NumericCode: "554", Name: "New Zealand Dollar", MinorUnits: 2},
+	{Code: "OMR", NumericCode: "512", Name: "Rial Omani", MinorUnits: 3},
+	{Code: "PAB", NumericCode: "590", Name: "Balboa", MinorUnits: 2},
+	{Code: "PEN", NumericCode: "604", Name: "Sol", MinorUnits: 2},
+	{Code: "PGK", NumericCode: "598", Name: "Kina", MinorUnits: 2},
+	{Code: "PHP", NumericCode: "608", Name: "Philippine Peso", MinorUnits: 2},
+	{Code: "PKR", NumericCode: "586", Name: "Pakistan Rupee", MinorUnits: 2},
+	{Code: "PLN", NumericCode: "985", Name: "Zloty", MinorUnits: 2},
+	{Code: "PYG", NumericCode: "600", Name: "Guarani", MinorUnits: 0},
+	{Code: "QAR", NumericCode: "634", Name: "Qatari Rial", MinorUnits: 2},
+	{Code: "RON", NumericCode: "946", Name: "Romanian Leu", MinorUnits: 2},
+	{Code: "RSD", NumericCode: "941", Name: "Serbian Dinar", MinorUnits: 2},
+	{Code: "RUB", NumericCode: "643", Name: "Russian Ruble", MinorUnits: 2},
+	{Code: "RWF", NumericCode: "646", Name: "Rwanda Franc", MinorUnits: 0},
+	{Code: "SAR", NumericCode: "682", Name: "Saudi Riyal", MinorUnits: 2},
+	{Code: "SBD", NumericCode: "090", Name: "Solomon Islands Dollar", MinorUnits: 2},
+	{Code: "SCR", NumericCode: "690", Name: "Seychelles Rupee", MinorUnits: 2},
+	{Code: "SDG", NumericCode: "938", Name: "Sudanese Pound", MinorUnits: 2},
+	{Code: "SEK", NumericCode: "752", Name: "Swedish Krona", MinorUnits: 2},
+	{Code: "SGD", NumericCode: "702", Name: "Singapore Dollar", MinorUnits: 2},
+	{Code: "SHP", NumericCode: "654", Name: "Saint Helena Pound", MinorUnits: 2},
+	{Code: "SLE", NumericCode: "925", Name: "Leone", MinorUnits: 2},
+	{Code: "SOS", NumericCode: "706", Name: "Somali Shilling", MinorUnits: 2},
+	{Code: "SRD", NumericCode: "968", Name: "Surinam Dollar", MinorUnits: 2},
+	{Code: "SSP", NumericCode: "728", Name: "South Sudanese Pound", MinorUnits: 2},
+	{Code: "STN", NumericCode: "930", Name: "Dobra", MinorUnits: 2},
+	{Code: "SVC", NumericCode: "222", Name: "El Salvador Colon", MinorUnits: 2},
+	{Code: "SYP", NumericCode: "760", Name: "Syrian Pound", MinorUnits: 2},
+	{Code: "SZL", NumericCode: "748", Name: "Lilangeni", MinorUnits: 2},
+	{Code: "THB", NumericCode: "764", Name: "Baht", MinorUnits: 2},
+	{Code: "TJS", NumericCode: "972", Name: "Somoni", MinorUnits: 2},
+	{Code: "TMT", NumericCode: "934", Name: "Turkmenistan New Manat", MinorUnits: 2},
+	{Code: "TND", NumericCode: "788", Name: "Tunisian Dinar", MinorUnits: 3},
+	{Code: "TOP", NumericCode: "776", Name: "Pa'anga", MinorUnits: 2},
+	{Code: "TRY", NumericCode: "949", Name: "Turkish Lira", MinorUnits: 2},
+	{Code: "TTD", NumericCode: "780", Name: "Trinidad and Tobago Dollar", MinorUnits: 2},
+	{Code: "TWD", NumericCode: "901", Name: "New Taiwan Dollar", MinorUnits: 2},
+	{Code: "TZS", NumericCode: "834", Name: "Tanzanian Shilling", MinorUnits: 2},
+	{Code: "UAH", NumericCode: "980", Name: "Hryvnia", MinorUnits: 2},
+	{Code: "UGX", NumericCode: "800", Name: "Uganda Shilling", MinorUnits: 0},
+	{Code: "USD", NumericCode: "840", Name: "US Dollar", MinorUnits: 2},
+	{Code: "UYU", NumericCode: "858", Name: "Peso Uruguayo", MinorUnits: 2},
+	{Code: "UZS", NumericCode: "860", Name: "Uzbekistan Sum", MinorUnits: 2},
+	{Code: "VED", NumericCode: "926", Name: "Bolívar Soberano", MinorUnits: 2},
+	{Code: "VES", NumericCode: "928", Name: "Bolívar Soberano", MinorUnits: 2},
+	{Code: "VND", NumericCode: "704", Name: "Dong", MinorUnits: 0},
+	{Code: "VUV", NumericCode: "548", Name: "Vatu", MinorUnits: 0},
+	{Code: "WST", NumericCode: "882", Name: "Tala", MinorUnits: 2},
+	{Code: "XAF", NumericCode: "950", Name: "CFA Franc BEAC", MinorUnits: 0},
+	{Code: "XCD", NumericCode: "951", Name: "East Caribbean Dollar", MinorUnits: 2},
+	{Code: "XCG", NumericCode: "532", Name: "Caribbean Guilder", MinorUnits: 2},
+	{Code: "XOF", NumericCode: "952", Name: "CFA Franc BCEAO", MinorUnits: 0},
+	{Code: "XPF", NumericCode: "953", Name: "CFP Franc", MinorUnits: 0},
+	{Code: "YER", NumericCode: "886", Name: "Yemeni Rial", MinorUnits: 2},
+	{Code: "ZAR", NumericCode: "710", Name: "Rand", MinorUnits: 2},
+	{Code: "ZMW", NumericCode: "967", Name: "Zambian Kwacha", MinorUnits: 2},
+	{Code: "ZWG", NumericCode: "924", Name: "Zimbabwe Gold", MinorUnits: 2},
+}
+
+var currenciesByCode = func() map[string]Currency {
+	currencies := make(map[string]Currency, len(iso4217))
+	for _, currency := range iso4217 {
+		currencies[currency.Code] = currency
+	}
+	return currencies
+}()
+
+// LookupCurrency returns the ISO 4217 currency with the code, whether a deployment enables it or not
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currenciesByCode[code]
+	return currency, ok
+}
+
+// CurrencyRegistry holds the currencies a deployment accepts
+type CurrencyRegistry struct {
+	currencies []Currency
+	byCode     map[string]Currency
+}
+
+// NewCurrencyRegistry enables the currencies with the given ISO 4217 codes, or every currency if there are none
+func NewCurrencyRegistry(codes []string) (*CurrencyRegistry, error) {
+	registry := &CurrencyRegistry{byCode: make(map[string]Currency)}
+
+	if len(codes) == 0 {
+		registry.currencies = append(registry.currencies, iso4217...)
+		for _, currency := range iso4217 {
+			registry.byCode[currency.Code] = currency
+		}
+		return registry, nil
+	}
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		currency, ok := LookupCurrency(code)
+		if !ok {
+			return nil, fmt.Errorf("unknown ISO 4217 currency %q", code)
+		}
+		if _, ok := registry.byCode[code]; ok {
+			continue
+		}
+
+		registry.currencies = append(registry.currencies, currency)
+		registry.byCode[code] = currency
 	}
-	return false
+
+	sort.Slice(registry.currencies, func(i, j int) bool {
+		return registry.currencies[i].Code < registry.currencies[j].Code
+	})
+
+	return registry, nil
+}
+
+// Lookup returns the enabled currency with the code
+func (registry *CurrencyRegistry) Lookup(code string) (Currency, bool) {
+	currency, ok := registry.byCode[code]
+	return currency, ok
+}
+
+// IsSupported reports whether the currency with the code is enabled
+func (registry *CurrencyRegistry) IsSupported(code string) bool {
+	_, ok := registry.byCode[code]
+	return ok
+}
+
+// Currencies returns the enabled currencies ordered by code
+func (registry *CurrencyRegistry) Currencies() []Currency {
+	return append([]Currency(nil), registry.currencies...)
 }
@@ -0,0 +1,140 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrMoneyOverflow is returned when an amount doesn't fit in an int64 of minor units
+	ErrMoneyOverflow = errors.New("amount overflows")
+	// ErrCurrencyMismatch is returned when adding or subtracting amounts of different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money is an amount in the minor unit of its currency, such as cents for USD
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// ParseMoney parses a decimal amount such as "12.34" or "-5" into the minor units of currency.
+// It rejects more decimal places than the currency has.
+func ParseMoney(value, currency string) (Money, error) {
+	info, ok := LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("unknown currency %q", currency)
+	}
+
+	digits, negative := strings.CutPrefix(value, "-")
+	whole, fraction, hasPoint := strings.Cut(digits, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > info.MinorUnits {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s", value, info.MinorUnits, currency)
+	}
+
+	amount, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", info.MinorUnits-len(fraction)), 10)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		amount.Neg(amount)
+	}
+	if !amount.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s", ErrMoneyOverflow, value)
+	}
+
+	return Money{Amount: amount.Int64(), Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns the sum of two amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts of the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+
+	if (other.Amount < 0 && m.Amount > math.MaxInt64+other.Amount) ||
+		(other.Amount > 0 && m.Amount < math.MinInt64+other.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Decimal formats the amount with the decimal places of its currency, like "12.34".
+// Amounts of a currency missing from ISO 4217 are formatted in minor units.
+func (m Money) Decimal() string {
+	info, _ := LookupCurrency(m.Currency)
+
+	// formatting the absolute value as unsigned keeps math.MinInt64 exact
+	abs := uint64(m.Amount)
+	sign := ""
+	if m.Amount < 0 {
+		abs, sign = -abs, "-"
+	}
+	if info.MinorUnits == 0 {
+		return fmt.Sprintf("%s%d", sign, abs)
+	}
+
+	scale := uint64(math.Pow10(info.MinorUnits))
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/scale, info.MinorUnits, abs%scale)
+}
+
+// String formats the amount followed by its currency, like "12.34 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// moneyJSON is how Money is sent in JSON, with a decimal string amount so no precision is lost
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value moneyJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseMoney(value.Amount, value.Currency)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
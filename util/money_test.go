@@ -0,0 +1,96 @@
+package util
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	testCases := []struct {
+		value    string
+		currency string
+		amount   int64
+		ok       bool
+	}{
+		{"12.34", USD, 1234, true},
+		{"12.3", USD, 1230, true},
+		{"12", USD, 1200, true},
+		{"-0.05", USD, -5, true},
+		{"500", "JPY", 500, true},
+		{"1.234", "KWD", 1234, true},
+		{"92233720368547758.07", USD, math.MaxInt64, true},
+		{"92233720368547758.08", USD, 0, false},
+		{"12.345", USD, 0, false},
+		{"5.5", "JPY", 0, false},
+		{"12.", USD, 0, false},
+		{".5", USD, 0, false},
+		{"1,000", USD, 0, false},
+		{"+1", USD, 0, false},
+		{"", USD, 0, false},
+		{"1", "XYZ", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value+" "+tc.currency, func(t *testing.T) {
+			money, err := ParseMoney(tc.value, tc.currency)
+			if !tc.ok {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, Money{Amount: tc.amount, Currency: tc.currency}, money)
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	require.Equal(t, "12.34", Money{Amount: 1234, Currency: USD}.Decimal())
+	require.Equal(t, "0.05", Money{Amount: 5, Currency: USD}.Decimal())
+	require.Equal(t, "-0.05", Money{Amount: -5, Currency: USD}.Decimal())
+	require.Equal(t, "500", Money{Amount: 500, Currency: "JPY"}.Decimal())
+	require.Equal(t, "1.234", Money{Amount: 1234, Currency: "KWD"}.Decimal())
+	require.Equal(t, "-92233720368547758.08", Money{Amount: math.MinInt64, Currency: USD}.Decimal())
+	require.Equal(t, "12.34 EUR", Money{Amount: 1234, Currency: EUR}.String())
+}
+
+func TestMoneyAddSub(t *testing.T) {
+	sum, err := Money{Amount: 1000, Currency: USD}.Add(Money{Amount: 234, Currency: USD})
+	require.NoError(t, err)
+	require.Equal(t, Money{Amount: 1234, Currency: USD}, sum)
+
+	difference, err := Money{Amount: 1000, Currency: USD}.Sub(Money{Amount: 1234, Currency: USD})
+	require.NoError(t, err)
+	require.Equal(t, Money{Amount: -234, Currency: USD}, difference)
+
+	_, err = Money{Amount: 1, Currency: USD}.Add(Money{Amount: 1, Currency: EUR})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = Money{Amount: math.MaxInt64, Currency: USD}.Add(Money{Amount: 1, Currency: USD})
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = Money{Amount: math.MinInt64, Currency: USD}.Add(Money{Amount: -1, Currency: USD})
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = Money{Amount: math.MinInt64, Currency: USD}.Sub(Money{Amount: 1, Currency: USD})
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+
+	_, err = Money{Amount: 0, Currency: USD}.Sub(Money{Amount: math.MinInt64, Currency: USD})
+	require.ErrorIs(t, err, ErrMoneyOverflow)
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(Money{Amount: 1234, Currency: USD})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"amount": "12.34", "currency": "USD"}`, string(data))
+
+	var money Money
+	err = json.Unmarshal([]byte(`{"amount": "0.5", "currency": "EUR"}`), &money)
+	require.NoError(t, err)
+	require.Equal(t, Money{Amount: 50, Currency: EUR}, money)
+
+	err = json.Unmarshal([]byte(`{"amount": "0.555", "currency": "EUR"}`), &money)
+	require.Error(t, err)
+}
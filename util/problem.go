@@ -0,0 +1,127 @@
+package util
+
+import "net/http"
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// problemTypeBase prefixes the code of a problem to form its type URI
+const problemTypeBase = "/problems/"
+
+// Stable error codes reported in problem details by both the HTTP API and the gRPC gateway
+const (
+	ProblemInvalidRequest         = "invalid_request"
+	ProblemValidationFailed       = "validation_failed"
+	ProblemUnauthenticated        = "unauthenticated"
+	ProblemForbidden              = "forbidden"
+	ProblemNotFound               = "not_found"
+	ProblemMethodNotAllowed       = "method_not_allowed"
+	ProblemAlreadyExists          = "already_exists"
+	ProblemInvalidReference       = "invalid_reference"
+	ProblemConflict               = "conflict"
+	ProblemInsufficientFunds      = "insufficient_funds"
+	ProblemHoldNotActive          = "hold_not_active"
+	ProblemReversalNotAllowed     = "reversal_not_allowed"
+	ProblemReversalAmountExceeded = "reversal_amount_exceeded"
+
+	ProblemScheduledTransferNotPending = "scheduled_transfer_not_pending"
+	ProblemStandingOrderNotActive      = "standing_order_not_active"
+	ProblemIdempotencyKeyReused        = "idempotency_key_reused"
+	ProblemTransferBatchFailed         = "transfer_batch_failed"
+	ProblemInternal                    = "internal"
+)
+
+// problemType is how every problem with a code is reported
+type problemType struct {
+	// status is the HTTP status, or 0 if the problem takes the status of the error it describes
+	status int
+	// detail explains the problem without any of the underlying error
+	detail string
+}
+
+var problemTypes = map[string]problemType{
+	ProblemInvalidRequest:         {http.StatusBadRequest, "The request is invalid."},
+	ProblemValidationFailed:       {http.StatusBadRequest, "The request has invalid fields."},
+	ProblemUnauthenticated:        {http.StatusUnauthorized, "The request lacks valid credentials."},
+	ProblemForbidden:              {http.StatusForbidden, "The request is not allowed for the authenticated user."},
+	ProblemNotFound:               {http.StatusNotFound, "The requested resource does not exist."},
+	ProblemMethodNotAllowed:       {http.StatusMethodNotAllowed, "The method is not allowed on the resource."},
+	ProblemAlreadyExists:          {http.StatusForbidden, "The resource already exists."},
+	ProblemInvalidReference:       {http.StatusForbidden, "The request refers to a resource that does not exist."},
+	ProblemConflict:               {http.StatusConflict, "The request conflicted with a concurrent one and can be retried."},
+	ProblemInsufficientFunds:      {http.StatusUnprocessableEntity, "The account balance doesn't cover the amount."},
+	ProblemHoldNotActive:          {http.StatusConflict, "The hold is no longer active."},
+	ProblemReversalNotAllowed:     {http.StatusConflict, "The transaction can't be reversed."},
+	ProblemReversalAmountExceeded: {http.StatusUnprocessableEntity, "The reversal exceeds the amount left to reverse."},
+
+	ProblemScheduledTransferNotPending: {http.StatusConflict, "The scheduled transfer is no longer pending."},
+	ProblemStandingOrderNotActive:      {http.StatusConflict, "The standing order is no longer active."},
+	ProblemIdempotencyKeyReused:        {http.StatusUnprocessableEntity, "The idempotency key was already used with a different request."},
+	ProblemTransferBatchFailed:         {0, "The transfer batch failed."},
+	ProblemInternal:                    {http.StatusInternalServerError, "The server failed to process the request."},
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     string            `json:"code"`
+	Errors   []FieldError      `json:"errors,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// FieldError describes why a single field of the request was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// NewProblem creates the problem details of a response with the given status and error code
+func NewProblem(status int, code, detail, instance string) Problem {
+	return Problem{
+		Type:     problemTypeBase + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+	}
+}
+
+// ProblemStatus returns the HTTP status of a problem code, and false if the code has no fixed status
+func ProblemStatus(code string) (int, bool) {
+	status := problemTypes[code].status
+	return status, status != 0
+}
+
+// ProblemDetail returns the detail a problem with the code is reported with
+func ProblemDetail(code string) string {
+	return problemTypes[code].detail
+}
+
+// ProblemCode returns the error code of a response status that has no more specific code
+func ProblemCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ProblemInvalidRequest
+	case http.StatusUnauthorized:
+		return ProblemUnauthenticated
+	case http.StatusForbidden:
+		return ProblemForbidden
+	case http.StatusNotFound:
+		return ProblemNotFound
+	case http.StatusMethodNotAllowed:
+		return ProblemMethodNotAllowed
+	case http.StatusConflict:
+		return ProblemConflict
+	}
+
+	if status >= http.StatusInternalServerError {
+		return ProblemInternal
+	}
+	return ProblemInvalidRequest
+}
@@ -35,9 +35,19 @@ func RandomMoney() int64 {
 	return RandomInt(0, 1000000)
 }
 
-// RandomCurrency generates a random currency code
+// RandomCurrency generates a random code of a currency enabled out of the box
 func RandomCurrency() string {
-	currencies := []string{"USD", "EUR", "CAD", "GBP", "JPY"}
+	currencies := []string{USD, EUR, CAD}
 	n := len(currencies)
 	return currencies[rand.Intn(n)]
 }
+
+// RandomOwner generates a random owner name
+func RandomOwner() string {
+	return RandomString(6)
+}
+
+// RandomEmail generates a random email
+func RandomEmail() string {
+	return RandomString(6) + "@email.com"
+}
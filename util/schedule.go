@@ -0,0 +1,102 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Keywords of schedules repeating at the time of their start
+const (
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+)
+
+// ErrScheduleNeverOccurs is returned for cron expressions that match no time, such as February 30
+var ErrScheduleNeverOccurs = errors.New("schedule never occurs")
+
+// cronParser accepts the five fields of a standard cron expression: minute, hour, day of month, month and day of week
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// Schedule gives the occurrences of a recurring event in UTC
+type Schedule struct {
+	start time.Time
+	// days and months step a keyword schedule from its start; both are 0 for a cron expression
+	days   int
+	months int
+	cron   cron.Schedule
+}
+
+// ParseSchedule parses daily, weekly and monthly, which repeat at the time of day, weekday or day of the month
+// of start, or a five-field cron expression such as "0 9 1 * *". Monthly schedules starting on a day some
+// months lack, like the 31st, fall on the last day of those months.
+func ParseSchedule(expr string, start time.Time) (Schedule, error) {
+	schedule := Schedule{start: start.UTC()}
+
+	switch expr {
+	case ScheduleDaily:
+		schedule.days = 1
+	case ScheduleWeekly:
+		schedule.days = 7
+	case ScheduleMonthly:
+		schedule.months = 1
+	default:
+		var err error
+		schedule.cron, err = cronParser.Parse(expr)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		if schedule.First().IsZero() {
+			return Schedule{}, fmt.Errorf("%w: %s", ErrScheduleNeverOccurs, expr)
+		}
+	}
+
+	return schedule, nil
+}
+
+// First returns the first occurrence at or after the start of the schedule
+func (s Schedule) First() time.Time {
+	return s.Next(s.start.Add(-time.Nanosecond))
+}
+
+// Next returns the first occurrence after t, or the zero time if a cron expression has no further match
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+	if s.cron != nil {
+		return s.cron.Next(t)
+	}
+
+	if t.Before(s.start) {
+		return s.start
+	}
+
+	// start from an estimate of the occurrences since the start, which may overshoot by one
+	var n int
+	if s.months > 0 {
+		n = ((t.Year()-s.start.Year())*12 + int(t.Month()-s.start.Month())) / s.months
+	} else {
+		n = int(t.Sub(s.start) / (time.Duration(s.days) * 24 * time.Hour))
+	}
+	n = max(n-1, 0)
+
+	for !s.occurrence(n).After(t) {
+		n++
+	}
+	return s.occurrence(n)
+}
+
+// occurrence returns the nth occurrence of a keyword schedule, counting the start as 0
+func (s Schedule) occurrence(n int) time.Time {
+	if s.months == 0 {
+		return s.start.AddDate(0, 0, n*s.days)
+	}
+
+	// AddDate would roll January 31 over into March; stay on the last day of the month instead
+	first := time.Date(s.start.Year(), s.start.Month()+time.Month(n*s.months), 1, 0, 0, 0, 0, time.UTC)
+	day := min(s.start.Day(), first.AddDate(0, 1, -1).Day())
+	return time.Date(first.Year(), first.Month(), day,
+		s.start.Hour(), s.start.Minute(), s.start.Second(), s.start.Nanosecond(), time.UTC)
+}
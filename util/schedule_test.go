@@ -0,0 +1,82 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		expr        string
+		occurrences []time.Time
+	}{
+		{ScheduleDaily, []time.Time{
+			start,
+			time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.February, 2, 9, 30, 0, 0, time.UTC),
+		}},
+		{ScheduleWeekly, []time.Time{
+			start,
+			time.Date(2024, time.February, 7, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.February, 14, 9, 30, 0, 0, time.UTC),
+		}},
+		{ScheduleMonthly, []time.Time{
+			start,
+			time.Date(2024, time.February, 29, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.March, 31, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.April, 30, 9, 30, 0, 0, time.UTC),
+		}},
+		{"0 9 1 * *", []time.Time{
+			time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC),
+		}},
+		{"30 9 * * 1-5", []time.Time{
+			start,
+			time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.February, 2, 9, 30, 0, 0, time.UTC),
+			time.Date(2024, time.February, 5, 9, 30, 0, 0, time.UTC),
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(tc.expr, start)
+			require.NoError(t, err)
+
+			occurrence := schedule.First()
+			for _, want := range tc.occurrences {
+				require.Equal(t, want, occurrence)
+				occurrence = schedule.Next(occurrence)
+			}
+		})
+	}
+}
+
+func TestScheduleNextSkipsAhead(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	schedule, err := ParseSchedule(ScheduleMonthly, start)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, time.February, 28, 9, 30, 0, 0, time.UTC), schedule.Next(time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, time.Date(2025, time.March, 31, 9, 30, 0, 0, time.UTC), schedule.Next(time.Date(2025, time.February, 28, 9, 30, 0, 0, time.UTC)))
+
+	schedule, err = ParseSchedule(ScheduleWeekly, start)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, time.March, 6, 9, 30, 0, 0, time.UTC), schedule.Next(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	for _, expr := range []string{"", "yearly", "@daily", "* * *", "0 9 1 * * *", "61 * * * *"} {
+		_, err := ParseSchedule(expr, start)
+		require.Error(t, err, expr)
+	}
+
+	_, err := ParseSchedule("0 0 30 2 *", start)
+	require.ErrorIs(t, err, ErrScheduleNeverOccurs)
+}